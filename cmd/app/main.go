@@ -3,14 +3,20 @@ package main
 import (
 	"context"
 	_ "embed"
+	"fmt"
 	"github.com/vcraescu/gsh-assessment/internal/adapters"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"github.com/vcraescu/gsh-assessment/internal/bootstrap"
 	"github.com/vcraescu/gsh-assessment/internal/domain"
 	"github.com/vcraescu/gsh-assessment/internal/gateways/httpx"
+	"github.com/vcraescu/gsh-assessment/internal/health"
 	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/internal/idempotency"
+	"github.com/vcraescu/gsh-assessment/internal/ratelimit"
 	"github.com/vcraescu/gsh-assessment/pkg/log"
-	"github.com/vcraescu/gsh-assessment/web"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/sdk/trace"
+	"github.com/vcraescu/gsh-assessment/pkg/metrics"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -18,41 +24,163 @@ import (
 )
 
 const (
-	gracefulShutdownTimeout = time.Second
-	serverAddress           = ":3000"
+	serviceName          = "gsh-assessment"
+	serverAddress        = ":3000"
+	defaultShutdownDelay = 5 * time.Second
+	tracerFlushTimeout   = 5 * time.Second
+	idempotencyTTL       = 24 * time.Hour
+	ordersRateLimit      = 10
+	ordersRateBurst      = 20
 )
 
 func main() {
-	tp := trace.NewTracerProvider(trace.WithSampler(trace.AlwaysSample()))
-	otel.SetTracerProvider(tp)
+	rt, err := bootstrap.Setup(context.Background(), serviceName, "app")
+	if err != nil {
+		panic(err)
+	}
 
 	var (
-		tracer = otel.Tracer("app")
-		logger = log.NewLogger()
-		ctx    = gracefulShutdown(context.Background(), logger)
-		srv    = httpserver.NewTraced(httpserver.New(logger), tracer)
+		logger    = rt.Logger
+		ctx       = gracefulShutdown(context.Background(), logger)
+		readiness = httpserver.NewReadiness()
 	)
 
-	defer tp.Shutdown(ctx)
+	authenticators, err := auth.FromEnv()
+	if err != nil {
+		panic(err)
+	}
 
 	repository, err := adapters.NewPackRepository()
 	if err != nil {
 		panic(err)
 	}
 
-	svc := domain.NewOrderService(repository)
-	createOrderHandler := httpx.NewCreateOrderHandler(svc, logger)
-	healthzCheckHandler := httpx.NewHealthzCheckHandler()
+	registry := metrics.NewRegistry()
 
-	srv.Get("/", web.StaticHandler)
-	srv.Post("/orders", createOrderHandler)
-	srv.Get("/healthz", healthzCheckHandler)
+	accessLogOpts, err := accessLogOptions()
+	if err != nil {
+		panic(err)
+	}
+
+	srv := httpserver.NewTraced(httpserver.New(logger, append(accessLogOpts,
+		httpserver.Use(auth.Middleware(authenticators...)),
+		httpserver.WithMetrics(registry),
+	)...), rt.Tracer)
 
-	if err := httpserver.Start(ctx, logger, srv, serverAddress); err != nil {
+	opts, err := serverOptions()
+	if err != nil {
 		panic(err)
 	}
+
+	opts.Readiness = readiness
+
+	svc := rt.OrderService(repository, domain.WithOrderObserver(adapters.NewOrderMetrics(registry)))
+
+	var (
+		ordersLimiter    = ratelimit.New(ordersRateLimit, ordersRateBurst)
+		idempotencyStore = idempotency.NewMemoryStore()
+		checks           = health.New()
+	)
+
+	// draining must be visible at once, so the shutdown check isn't cached
+	checks.RegisterReadiness("shutdown", readiness.Check, health.WithCacheTTL(0))
+	checks.RegisterReadiness("packs", repository.Check)
+	checks.RegisterReadiness("idempotency_store", idempotencyStore.Check, health.WithSeverity(health.NonCritical))
+
+	if opts.TLS != nil {
+		checks.RegisterReadiness("tls_certificate", opts.TLS.Check, health.WithSeverity(health.NonCritical))
+	}
+
+	httpx.RegisterRoutes(srv, svc, logger,
+		httpx.WithRouteMiddleware(http.MethodPost, "/orders", ordersLimiter.Middleware()),
+		httpx.WithRouteMiddleware(http.MethodGet, "/orders/quote", ordersLimiter.Middleware()),
+		httpx.WithIdempotency(idempotencyStore, idempotencyTTL),
+		httpx.WithHealth(checks),
+		httpx.WithMetrics(registry),
+		httpx.WithLogLevel(rt.LogLevel),
+	)
+
+	serveErr := httpserver.Start(ctx, logger, srv, opts)
+
+	// ctx is already cancelled by now
+	flushCtx, cancel := context.WithTimeout(context.Background(), tracerFlushTimeout)
+	defer cancel()
+
+	if err := rt.TracerProvider.Shutdown(flushCtx); err != nil {
+		logger.Error(flushCtx, "tracer provider shutdown failed", log.Error(err))
+	}
+
+	if serveErr != nil {
+		panic(serveErr)
+	}
+}
+
+// serverOptions enables TLS when TLS_CERT_FILE and TLS_KEY_FILE are set and
+// mutual TLS when TLS_CLIENT_CA_FILE is set too. SHUTDOWN_DELAY and
+// SHUTDOWN_TIMEOUT accept time.ParseDuration values.
+func serverOptions() (httpserver.Options, error) {
+	opts := httpserver.Options{
+		Address:       serverAddress,
+		ShutdownDelay: defaultShutdownDelay,
+	}
+
+	if v := os.Getenv("SHUTDOWN_DELAY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("SHUTDOWN_DELAY: %w", err)
+		}
+
+		opts.ShutdownDelay = d
+	}
+
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("SHUTDOWN_TIMEOUT: %w", err)
+		}
+
+		opts.ShutdownTimeout = d
+	}
+
+	certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	if certFile == "" || keyFile == "" {
+		return opts, nil
+	}
+
+	opts.TLS = &httpserver.TLSOptions{
+		CertFile:          certFile,
+		KeyFile:           keyFile,
+		ClientCAFile:      os.Getenv("TLS_CLIENT_CA_FILE"),
+		RequireClientCert: os.Getenv("TLS_REQUIRE_CLIENT_CERT") == "true",
+	}
+
+	return opts, nil
+}
+
+// accessLogOptions trusts the X-Forwarded-For header of the proxies listed in
+// TRUSTED_PROXIES and writes the access log to stdout in the Apache combined
+// format when ACCESS_LOG_FORMAT is combined.
+func accessLogOptions() ([]httpserver.Option, error) {
+	proxies, err := httpserver.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+
+	opts := []httpserver.Option{httpserver.WithTrustedProxies(proxies...)}
+
+	switch format := os.Getenv("ACCESS_LOG_FORMAT"); format {
+	case "", "json":
+	case "combined":
+		opts = append(opts, httpserver.WithCombinedAccessLog(os.Stdout))
+	default:
+		return nil, fmt.Errorf("ACCESS_LOG_FORMAT: unknown format %q", format)
+	}
+
+	return opts, nil
 }
 
+// gracefulShutdown returns a context cancelled on SIGINT or SIGTERM; draining
+// is up to httpserver.Start.
 func gracefulShutdown(ctx context.Context, logger log.Logger) context.Context {
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -61,11 +189,10 @@ func gracefulShutdown(ctx context.Context, logger log.Logger) context.Context {
 
 	go func() {
 		defer cancel()
-		<-sigCh
 
-		logger.Info(ctx, "shutting down server...")
+		sig := <-sigCh
 
-		time.Sleep(gracefulShutdownTimeout)
+		logger.Info(ctx, "signal received", slog.String("signal", sig.String()))
 	}()
 
 	return ctx
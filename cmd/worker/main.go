@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/vcraescu/gsh-assessment/internal/adapters"
+	"github.com/vcraescu/gsh-assessment/internal/bootstrap"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/sqsx"
+	"os"
+)
+
+const serviceName = "gsh-assessment-worker"
+
+func main() {
+	rt, err := bootstrap.Setup(context.Background(), serviceName, "worker")
+	if err != nil {
+		panic(err)
+	}
+
+	repository, err := adapters.NewPackRepository()
+	if err != nil {
+		panic(err)
+	}
+
+	// results are written to the function's log stream until a consumer needs
+	// them elsewhere
+	ordersHandler := sqsx.NewHandler(rt.OrderService(repository), sqsx.NewJSONSink(os.Stdout), rt.Logger)
+
+	lambda.Start(bootstrap.LambdaHandler(rt, ordersHandler.Handle))
+}
@@ -0,0 +1,42 @@
+package adapters
+
+import (
+	"context"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/pkg/metrics"
+	"time"
+)
+
+var _ domain.OrderObserver = (*OrderMetrics)(nil)
+
+// OrderMetrics records the orders solved by domain.OrderService.
+type OrderMetrics struct {
+	quantity       *metrics.Histogram
+	overshoot      *metrics.Histogram
+	packs          *metrics.Histogram
+	solverDuration *metrics.Histogram
+}
+
+func NewOrderMetrics(reg *metrics.Registry) *OrderMetrics {
+	return &OrderMetrics{
+		quantity: reg.Histogram("order_requested_quantity",
+			"Items requested per order.",
+			metrics.ExponentialBuckets(1, 10, 7)),
+		overshoot: reg.Histogram("order_overshoot_items",
+			"Items shipped beyond the quantity requested per order.",
+			[]float64{0, 1, 10, 50, 100, 250, 500, 1000, 5000}),
+		packs: reg.Histogram("order_packs",
+			"Packs shipped per order.",
+			metrics.ExponentialBuckets(1, 2, 12)),
+		solverDuration: reg.Histogram("order_solver_duration_seconds",
+			"Time spent choosing the packs for an order.",
+			metrics.ExponentialBuckets(1e-6, 4, 10)),
+	}
+}
+
+func (m *OrderMetrics) ObserveOrder(_ context.Context, quantity int, order domain.Order, solveDuration time.Duration) {
+	m.quantity.Observe(float64(quantity))
+	m.overshoot.Observe(float64(order.Items() - quantity))
+	m.packs.Observe(float64(order.Packs()))
+	m.solverDuration.Observe(solveDuration.Seconds())
+}
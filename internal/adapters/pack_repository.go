@@ -4,8 +4,8 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
-	"fmt"
 	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/pkg/errs"
 )
 
 //go:embed packs.json
@@ -19,7 +19,7 @@ func NewPackRepository() (*PackRepository, error) {
 	r := &PackRepository{}
 
 	if err := json.Unmarshal(packs, &r.data); err != nil {
-		return nil, fmt.Errorf("unmarshal: %w", err)
+		return nil, errs.Wrap(err, "unmarshal")
 	}
 
 	return r, nil
@@ -34,3 +34,17 @@ func (r *PackRepository) FindAll(_ context.Context) ([]domain.Pack, error) {
 
 	return out, nil
 }
+
+// Check fails when no pack sizes are configured, as no order could be fulfilled.
+func (r *PackRepository) Check(ctx context.Context) error {
+	packs, err := r.FindAll(ctx)
+	if err != nil {
+		return errs.Wrap(err, "findAll")
+	}
+
+	if len(packs) == 0 {
+		return errs.New("no pack sizes configured")
+	}
+
+	return nil
+}
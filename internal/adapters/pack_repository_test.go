@@ -17,3 +17,12 @@ func TestPackRepository_FindAll(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, got)
 }
+
+func TestPackRepository_Check(t *testing.T) {
+	t.Parallel()
+
+	r, err := adapters.NewPackRepository()
+	require.NoError(t, err)
+
+	require.NoError(t, r.Check(context.Background()))
+}
@@ -0,0 +1,137 @@
+package adapters
+
+import (
+	"context"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	_ OrderService          = (*TracedOrderService)(nil)
+	_ OrderService          = (*domain.OrderService)(nil)
+	_ domain.Solver         = (*TracedSolver)(nil)
+	_ domain.PackRepository = (*TracedPackRepository)(nil)
+)
+
+type OrderService interface {
+	Create(ctx context.Context, quantity int) (domain.Order, error)
+}
+
+// TracedOrderService wraps every order in an "OrderService.Create" span.
+type TracedOrderService struct {
+	next   OrderService
+	tracer trace.Tracer
+}
+
+func NewTracedOrderService(next OrderService, tracer trace.Tracer) *TracedOrderService {
+	return &TracedOrderService{next: next, tracer: tracer}
+}
+
+func (s *TracedOrderService) Create(ctx context.Context, quantity int) (domain.Order, error) {
+	ctx, span := s.tracer.Start(ctx, "OrderService.Create", trace.WithAttributes(
+		attribute.Int("order.quantity", quantity),
+	))
+	defer span.End()
+
+	order, err := s.next.Create(ctx, quantity)
+	if err != nil {
+		recordError(span, err)
+
+		return order, err
+	}
+
+	span.SetAttributes(orderAttributes(quantity, order)...)
+
+	return order, nil
+}
+
+// TracedSolver wraps every solver run in a "Solver.Solve" span.
+type TracedSolver struct {
+	next   domain.Solver
+	tracer trace.Tracer
+}
+
+func NewTracedSolver(next domain.Solver, tracer trace.Tracer) *TracedSolver {
+	return &TracedSolver{next: next, tracer: tracer}
+}
+
+func (s *TracedSolver) Strategy() string {
+	return s.next.Strategy()
+}
+
+func (s *TracedSolver) Solve(ctx context.Context, quantity int, packs []domain.Pack) (domain.Order, error) {
+	ctx, span := s.tracer.Start(ctx, "Solver.Solve", trace.WithAttributes(
+		attribute.String("solver.strategy", s.next.Strategy()),
+		attribute.Int("order.quantity", quantity),
+		attribute.Int("packs.count", len(packs)),
+		attribute.String("packs.version", domain.PackSetVersion(packs)),
+	))
+	defer span.End()
+
+	order, err := s.next.Solve(ctx, quantity, packs)
+	if err != nil {
+		recordError(span, err)
+
+		return order, err
+	}
+
+	span.SetAttributes(orderAttributes(quantity, order)...)
+
+	return order, nil
+}
+
+// TracePhases runs every solver phase in a "Solver.<phase>" span, e.g.
+// domain.GreedySolver{Phase: TracePhases(tracer)}.
+func TracePhases(tracer trace.Tracer) domain.PhaseFunc {
+	return func(ctx context.Context, name string, fn func(ctx context.Context)) {
+		ctx, span := tracer.Start(ctx, "Solver."+name)
+		defer span.End()
+
+		fn(ctx)
+	}
+}
+
+// TracedPackRepository wraps every lookup in a "PackRepository.FindAll" span.
+type TracedPackRepository struct {
+	next   domain.PackRepository
+	tracer trace.Tracer
+}
+
+func NewTracedPackRepository(next domain.PackRepository, tracer trace.Tracer) *TracedPackRepository {
+	return &TracedPackRepository{next: next, tracer: tracer}
+}
+
+func (r *TracedPackRepository) FindAll(ctx context.Context) ([]domain.Pack, error) {
+	ctx, span := r.tracer.Start(ctx, "PackRepository.FindAll")
+	defer span.End()
+
+	packs, err := r.next.FindAll(ctx)
+	if err != nil {
+		recordError(span, err)
+
+		return packs, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("packs.count", len(packs)),
+		attribute.String("packs.version", domain.PackSetVersion(packs)),
+	)
+
+	return packs, nil
+}
+
+func orderAttributes(quantity int, order domain.Order) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int("order.rows", len(order.Rows)),
+		attribute.Int("order.packs", order.Packs()),
+		attribute.Int("order.items", order.Items()),
+		attribute.Int("order.overshoot", order.Items()-quantity),
+	}
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
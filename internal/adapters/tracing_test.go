@@ -0,0 +1,105 @@
+package adapters_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/adapters"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+type failingRepository struct{}
+
+func (failingRepository) FindAll(context.Context) ([]domain.Pack, error) {
+	return nil, errors.New("unavailable")
+}
+
+func newTracedService(repository domain.PackRepository) (*adapters.TracedOrderService, *tracetest.SpanRecorder) {
+	var (
+		recorder = tracetest.NewSpanRecorder()
+		tracer   = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	)
+
+	svc := adapters.NewTracedOrderService(domain.NewOrderService(
+		adapters.NewTracedPackRepository(repository, tracer),
+		domain.WithSolver(adapters.NewTracedSolver(domain.GreedySolver{Phase: adapters.TracePhases(tracer)}, tracer)),
+	), tracer)
+
+	return svc, recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := make(map[attribute.Key]attribute.Value)
+
+	for _, kv := range span.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+
+	return out
+}
+
+func TestTracedOrderService_Create(t *testing.T) {
+	t.Parallel()
+
+	repository, err := adapters.NewPackRepository()
+	require.NoError(t, err)
+
+	svc, recorder := newTracedService(repository)
+
+	_, err = svc.Create(context.Background(), 251)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 7)
+
+	findAll, phases, solve, create := spans[0], spans[1:5], spans[5], spans[6]
+
+	require.Equal(t, "PackRepository.FindAll", findAll.Name())
+	require.Equal(t, "Solver.Solve", solve.Name())
+	require.Equal(t, "OrderService.Create", create.Name())
+	require.Equal(t, create.SpanContext().SpanID(), findAll.Parent().SpanID())
+	require.Equal(t, create.SpanContext().SpanID(), solve.Parent().SpanID())
+
+	for i, name := range []string{"Solver.sortPacks", "Solver.roundUp", "Solver.fill", "Solver.sortRows"} {
+		require.Equal(t, name, phases[i].Name())
+		require.Equal(t, solve.SpanContext().SpanID(), phases[i].Parent().SpanID())
+	}
+
+	packs, err := repository.FindAll(context.Background())
+	require.NoError(t, err)
+
+	version := domain.PackSetVersion(packs)
+
+	require.Equal(t, version, spanAttributes(findAll)["packs.version"].AsString())
+	require.Equal(t, version, spanAttributes(solve)["packs.version"].AsString())
+	require.Equal(t, "greedy", spanAttributes(solve)["solver.strategy"].AsString())
+
+	attrs := spanAttributes(create)
+	require.EqualValues(t, 251, attrs["order.quantity"].AsInt64())
+	require.EqualValues(t, 1, attrs["order.rows"].AsInt64())
+	require.EqualValues(t, 249, attrs["order.overshoot"].AsInt64())
+	require.Equal(t, codes.Unset, create.Status().Code)
+}
+
+func TestTracedOrderService_Create_error(t *testing.T) {
+	t.Parallel()
+
+	svc, recorder := newTracedService(failingRepository{})
+
+	_, err := svc.Create(context.Background(), 1)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	for _, span := range spans {
+		require.Equal(t, codes.Error, span.Status().Code, span.Name())
+		require.Len(t, span.Events(), 1, span.Name())
+		require.Equal(t, "exception", span.Events()[0].Name)
+	}
+}
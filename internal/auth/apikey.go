@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+
+	MethodAPIKey = "api_key"
+)
+
+var _ Authenticator = (*APIKeyAuthenticator)(nil)
+
+// APIKeyAuthenticator only keeps the SHA-256 hashes of the keys, so the
+// configuration never holds a usable secret.
+type APIKeyAuthenticator struct {
+	keys map[string]Principal
+}
+
+// NewAPIKeyAuthenticator takes the principals by the hex encoded SHA-256 of
+// their key; see HashAPIKey.
+func NewAPIKeyAuthenticator(keys map[string]Principal) *APIKeyAuthenticator {
+	a := &APIKeyAuthenticator{
+		keys: make(map[string]Principal, len(keys)),
+	}
+
+	for hash, p := range keys {
+		p.Method = MethodAPIKey
+		a.keys[hash] = p
+	}
+
+	return a
+}
+
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	key := r.Header.Get(HeaderAPIKey)
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	hash := HashAPIKey(key)
+
+	// compare every hash so the lookup time doesn't depend on which key matched
+	var (
+		found Principal
+		ok    bool
+	)
+
+	for h, p := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			found, ok = p, true
+		}
+	}
+
+	if !ok {
+		return Principal{}, ErrInvalidCredentials
+	}
+
+	return found, nil
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	EnvAPIKeys         = "AUTH_API_KEYS"
+	EnvJWTHMACSecret   = "AUTH_JWT_HMAC_SECRET"
+	EnvJWTRSAPublicKey = "AUTH_JWT_RSA_PUBLIC_KEY_FILE"
+	EnvJWTIssuer       = "AUTH_JWT_ISSUER"
+	EnvJWTAudience     = "AUTH_JWT_AUDIENCE"
+	EnvJWTMaxLifetime  = "AUTH_JWT_MAX_LIFETIME"
+)
+
+// FromEnv builds the authenticators configured in the environment:
+//
+//	AUTH_API_KEYS="<sha256 hex>:<subject>[:<role>,<role>];..."
+//	AUTH_JWT_HMAC_SECRET, AUTH_JWT_RSA_PUBLIC_KEY_FILE (PEM)
+//	AUTH_JWT_ISSUER, AUTH_JWT_AUDIENCE, AUTH_JWT_MAX_LIFETIME (e.g. 24h)
+func FromEnv() ([]Authenticator, error) {
+	var out []Authenticator
+
+	if v := os.Getenv(EnvAPIKeys); v != "" {
+		keys, err := parseAPIKeys(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvAPIKeys, err)
+		}
+
+		out = append(out, NewAPIKeyAuthenticator(keys))
+	}
+
+	var opts []JWTOption
+
+	if v := os.Getenv(EnvJWTHMACSecret); v != "" {
+		opts = append(opts, WithHMACKey([]byte(v)))
+	}
+
+	if v := os.Getenv(EnvJWTRSAPublicKey); v != "" {
+		key, err := readRSAPublicKey(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvJWTRSAPublicKey, err)
+		}
+
+		opts = append(opts, WithRSAPublicKey(key))
+	}
+
+	if v := os.Getenv(EnvJWTMaxLifetime); v != "" && len(opts) > 0 {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvJWTMaxLifetime, err)
+		}
+
+		opts = append(opts, WithMaxLifetime(d))
+	}
+
+	if len(opts) > 0 {
+		opts = append(opts, WithIssuer(os.Getenv(EnvJWTIssuer)), WithAudience(os.Getenv(EnvJWTAudience)))
+		out = append(out, NewJWTAuthenticator(opts...))
+	}
+
+	return out, nil
+}
+
+func parseAPIKeys(s string) (map[string]Principal, error) {
+	out := make(map[string]Principal)
+
+	for _, entry := range strings.Split(s, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts[0]) != 64 || parts[1] == "" {
+			return nil, fmt.Errorf("malformed entry %d", len(out)+1)
+		}
+
+		p := Principal{Subject: parts[1]}
+		if len(parts) > 2 && parts[2] != "" {
+			p.Roles = strings.Split(parts[2], ",")
+		}
+
+		out[strings.ToLower(parts[0])] = p
+	}
+
+	return out, nil
+}
+
+func readRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("readFile: %w", err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsePKIXPublicKey: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an RSA public key; got %T", key)
+	}
+
+	return rsaKey, nil
+}
@@ -0,0 +1,30 @@
+package auth_test
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(auth.EnvAPIKeys, auth.HashAPIKey("admin-key")+":ops:admin,reader; "+auth.HashAPIKey("k2")+":warehouse")
+	t.Setenv(auth.EnvJWTHMACSecret, string(hmacKey))
+
+	got, err := auth.FromEnv()
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(auth.HeaderAPIKey, "admin-key")
+
+	p, err := got[0].Authenticate(req)
+	require.NoError(t, err)
+	require.Equal(t, auth.Principal{Subject: "ops", Method: auth.MethodAPIKey, Roles: []string{"admin", "reader"}}, p)
+
+	t.Setenv(auth.EnvAPIKeys, "plain-key:ops")
+
+	_, err = auth.FromEnv()
+	require.Error(t, err)
+}
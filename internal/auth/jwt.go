@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	MethodJWT = "jwt"
+
+	algHS256 = "HS256"
+	algRS256 = "RS256"
+)
+
+var _ Authenticator = (*JWTAuthenticator)(nil)
+
+type JWTOption func(a *JWTAuthenticator)
+
+func WithHMACKey(key []byte) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.hmacKey = key
+	}
+}
+
+func WithRSAPublicKey(key *rsa.PublicKey) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.rsaKey = key
+	}
+}
+
+func WithIssuer(issuer string) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.issuer = issuer
+	}
+}
+
+func WithAudience(audience string) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.audience = audience
+	}
+}
+
+// WithLeeway tolerates clock skew when checking exp and nbf.
+func WithLeeway(d time.Duration) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.leeway = d
+	}
+}
+
+// WithMaxLifetime rejects tokens valid for longer than d, counted from iat or,
+// without it, from now.
+func WithMaxLifetime(d time.Duration) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.maxLifetime = d
+	}
+}
+
+func WithJWTClock(now func() time.Time) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.now = now
+	}
+}
+
+// JWTAuthenticator verifies HS256 and RS256 bearer tokens. Each algorithm is
+// accepted only when its key is configured, so a token can't pick the key.
+type JWTAuthenticator struct {
+	hmacKey     []byte
+	rsaKey      *rsa.PublicKey
+	issuer      string
+	audience    string
+	leeway      time.Duration
+	maxLifetime time.Duration
+	now         func() time.Time
+}
+
+func NewJWTAuthenticator(opts ...JWTOption) *JWTAuthenticator {
+	a := &JWTAuthenticator{
+		now: time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	Scope     string   `json:"scope,omitempty"`
+}
+
+// audience is either a single string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+
+		return nil
+	}
+
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+
+	*a = ss
+
+	return nil
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return Principal{}, ErrNoCredentials
+	}
+
+	claims, err := a.Verify(strings.TrimSpace(token))
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	return Principal{
+		Subject: claims.Subject,
+		Method:  MethodJWT,
+		Roles:   claims.Roles,
+		Scopes:  strings.Fields(claims.Scope),
+	}, nil
+}
+
+func (a *JWTAuthenticator) Verify(token string) (Claims, error) {
+	var claims Claims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, fmt.Errorf("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return claims, fmt.Errorf("header: %w", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, fmt.Errorf("signature: %w", err)
+	}
+
+	if err := a.verifySignature(header.Alg, parts[0]+"."+parts[1], sig); err != nil {
+		return claims, err
+	}
+
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return claims, fmt.Errorf("claims: %w", err)
+	}
+
+	return claims, a.validateClaims(claims)
+}
+
+func (a *JWTAuthenticator) verifySignature(alg, signed string, sig []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+
+	switch {
+	case alg == algHS256 && len(a.hmacKey) > 0:
+		mac := hmac.New(sha256.New, a.hmacKey)
+		mac.Write([]byte(signed))
+
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return fmt.Errorf("invalid signature")
+		}
+	case alg == algRS256 && a.rsaKey != nil:
+		if err := rsa.VerifyPKCS1v15(a.rsaKey, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	return nil
+}
+
+func (a *JWTAuthenticator) validateClaims(c Claims) error {
+	now := a.now()
+
+	// a token without exp would be valid forever
+	if c.ExpiresAt == 0 {
+		return fmt.Errorf("missing expiry")
+	}
+
+	expiresAt := time.Unix(c.ExpiresAt, 0)
+
+	if now.After(expiresAt.Add(a.leeway)) {
+		return fmt.Errorf("token expired")
+	}
+
+	if a.maxLifetime > 0 {
+		issuedAt := now
+		if c.IssuedAt != 0 {
+			issuedAt = time.Unix(c.IssuedAt, 0)
+		}
+
+		if expiresAt.Sub(issuedAt) > a.maxLifetime {
+			return fmt.Errorf("token lifetime exceeds %s", a.maxLifetime)
+		}
+	}
+
+	if c.NotBefore != 0 && now.Before(time.Unix(c.NotBefore, 0).Add(-a.leeway)) {
+		return fmt.Errorf("token not valid yet")
+	}
+
+	if a.issuer != "" && c.Issuer != a.issuer {
+		return fmt.Errorf("unexpected issuer %q", c.Issuer)
+	}
+
+	if a.audience != "" && !contains(c.Audience, a.audience) {
+		return fmt.Errorf("unexpected audience")
+	}
+
+	if c.Subject == "" {
+		return fmt.Errorf("missing subject")
+	}
+
+	return nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return nil
+}
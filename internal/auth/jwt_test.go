@@ -0,0 +1,214 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	hmacKey = []byte("secret")
+	now     = time.Unix(1_700_000_000, 0)
+)
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	a := auth.NewJWTAuthenticator(
+		auth.WithHMACKey(hmacKey),
+		auth.WithRSAPublicKey(&rsaKey.PublicKey),
+		auth.WithIssuer("issuer"),
+		auth.WithAudience("orders"),
+		auth.WithLeeway(time.Minute),
+		auth.WithMaxLifetime(2*time.Hour),
+		auth.WithJWTClock(func() time.Time { return now }),
+	)
+
+	valid := map[string]any{
+		"sub":   "alice",
+		"iss":   "issuer",
+		"aud":   []string{"other", "orders"},
+		"exp":   now.Add(time.Hour).Unix(),
+		"roles": []string{"admin"},
+		"scope": "orders:read orders:write",
+	}
+
+	with := func(key string, value any) map[string]any {
+		out := make(map[string]any, len(valid))
+		for k, v := range valid {
+			out[k] = v
+		}
+
+		out[key] = value
+
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		want    auth.Principal
+		wantErr error
+	}{
+		{
+			name:    "no header",
+			wantErr: auth.ErrNoCredentials,
+		},
+		{
+			name:    "other scheme",
+			header:  "Basic YWxpY2U6c2VjcmV0",
+			wantErr: auth.ErrNoCredentials,
+		},
+		{
+			name:   "HS256",
+			header: "Bearer " + signHS256(t, hmacKey, valid),
+			want: auth.Principal{
+				Subject: "alice",
+				Method:  auth.MethodJWT,
+				Roles:   []string{"admin"},
+				Scopes:  []string{"orders:read", "orders:write"},
+			},
+		},
+		{
+			name:   "RS256",
+			header: "Bearer " + signRS256(t, rsaKey, with("aud", "orders")),
+			want: auth.Principal{
+				Subject: "alice",
+				Method:  auth.MethodJWT,
+				Roles:   []string{"admin"},
+				Scopes:  []string{"orders:read", "orders:write"},
+			},
+		},
+		{
+			name:    "wrong HMAC key",
+			header:  "Bearer " + signHS256(t, []byte("other"), valid),
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "wrong RSA key",
+			header:  "Bearer " + signRS256(t, otherRSAKey, valid),
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "alg none",
+			header:  "Bearer " + encode(t, map[string]string{"alg": "none"}) + "." + encode(t, valid) + ".",
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "expired",
+			header:  "Bearer " + signHS256(t, hmacKey, with("exp", now.Add(-2*time.Minute).Unix())),
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:   "expired within leeway",
+			header: "Bearer " + signHS256(t, hmacKey, with("exp", now.Add(-30*time.Second).Unix())),
+			want: auth.Principal{
+				Subject: "alice",
+				Method:  auth.MethodJWT,
+				Roles:   []string{"admin"},
+				Scopes:  []string{"orders:read", "orders:write"},
+			},
+		},
+		{
+			name:    "missing expiry",
+			header:  "Bearer " + signHS256(t, hmacKey, with("exp", nil)),
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "lifetime too long",
+			header:  "Bearer " + signHS256(t, hmacKey, with("iat", now.Add(-3*time.Hour).Unix())),
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "not valid yet",
+			header:  "Bearer " + signHS256(t, hmacKey, with("nbf", now.Add(time.Hour).Unix())),
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "wrong issuer",
+			header:  "Bearer " + signHS256(t, hmacKey, with("iss", "other")),
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "wrong audience",
+			header:  "Bearer " + signHS256(t, hmacKey, with("aud", "other")),
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "malformed",
+			header:  "Bearer abc",
+			wantErr: auth.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			got, err := a.Authenticate(req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func signHS256(t *testing.T, key []byte, claims any) string {
+	t.Helper()
+
+	signed := encode(t, map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(t, claims)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims any) string {
+	t.Helper()
+
+	signed := encode(t, map[string]string{"alg": "RS256", "typ": "JWT"}) + "." + encode(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func encode(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"errors"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"log/slog"
+	"net/http"
+)
+
+const wwwAuthenticate = "Bearer, ApiKey"
+
+var (
+	ErrNoCredentials      = errors.New("no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	errAuthenticationRequired = errors.New("authentication required")
+	errForbidden              = errors.New("forbidden")
+)
+
+type Authenticator interface {
+	// Authenticate returns ErrNoCredentials when the request doesn't carry
+	// credentials this authenticator understands.
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Middleware identifies the caller with the first authenticator that finds
+// credentials. Anonymous requests pass through; use Require to reject them.
+func Middleware(authenticators ...Authenticator) httpserver.Middleware {
+	return func(next httpserver.HandlerFunc) httpserver.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			for _, a := range authenticators {
+				p, err := a.Authenticate(r)
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+
+				if err != nil {
+					w.Header().Set("WWW-Authenticate", wwwAuthenticate)
+
+					return httpserver.WriteError(w, r, http.StatusUnauthorized, ErrInvalidCredentials)
+				}
+
+				httpserver.SetUser(r.Context(), p.Subject)
+
+				ctx := ContextWithPrincipal(r.Context(), p)
+				ctx = log.ContextWithAttrs(ctx, slog.Group("principal",
+					slog.String("subject", p.Subject),
+					slog.String("method", p.Method),
+				))
+
+				return next(w, r.WithContext(ctx))
+			}
+
+			return next(w, r)
+		}
+	}
+}
+
+// RequireRoles rejects requests whose principal lacks any of roles.
+func RequireRoles(roles ...string) httpserver.Middleware {
+	return require(func(p Principal) bool {
+		for _, role := range roles {
+			if !p.HasRole(role) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// RequireScopes rejects requests whose principal lacks any of scopes.
+func RequireScopes(scopes ...string) httpserver.Middleware {
+	return require(func(p Principal) bool {
+		for _, scope := range scopes {
+			if !p.HasScope(scope) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func require(allowed func(p Principal) bool) httpserver.Middleware {
+	return func(next httpserver.HandlerFunc) httpserver.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			p, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				w.Header().Set("WWW-Authenticate", wwwAuthenticate)
+
+				return httpserver.WriteError(w, r, http.StatusUnauthorized, errAuthenticationRequired)
+			}
+
+			if !allowed(p) {
+				return httpserver.WriteError(w, r, http.StatusForbidden, errForbidden)
+			}
+
+			return next(w, r)
+		}
+	}
+}
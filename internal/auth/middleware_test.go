@@ -0,0 +1,159 @@
+package auth_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		apiKeys = auth.NewAPIKeyAuthenticator(map[string]auth.Principal{
+			auth.HashAPIKey("admin-key"):  {Subject: "ops", Roles: []string{"admin"}},
+			auth.HashAPIKey("client-key"): {Subject: "warehouse"},
+		})
+		jwt = auth.NewJWTAuthenticator(auth.WithHMACKey(hmacKey))
+		srv = httpserver.New(log.NewNopLogger(), httpserver.Use(auth.Middleware(apiKeys, jwt)))
+
+		expiry = time.Now().Add(time.Hour).Unix()
+	)
+
+	whoami := func(w http.ResponseWriter, r *http.Request) error {
+		p, _ := auth.PrincipalFromContext(r.Context())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(p.Subject))
+
+		return err
+	}
+
+	srv.Get("/public", whoami)
+
+	admin := httpserver.Group(srv, httpserver.WithMiddleware(auth.RequireRoles("admin")))
+	admin.Get("/admin", whoami)
+
+	srv.Get("/scoped", whoami, httpserver.WithMiddleware(auth.RequireScopes("orders:write")))
+
+	tests := []struct {
+		name     string
+		path     string
+		header   [2]string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "anonymous public",
+			path:     "/public",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "api key public",
+			path:     "/public",
+			header:   [2]string{auth.HeaderAPIKey, "client-key"},
+			wantCode: http.StatusOK,
+			wantBody: "warehouse",
+		},
+		{
+			name:     "invalid api key",
+			path:     "/public",
+			header:   [2]string{auth.HeaderAPIKey, "nope"},
+			wantCode: http.StatusUnauthorized,
+			wantBody: `{"error":"invalid credentials","requestId":"req-1"}`,
+		},
+		{
+			name:     "anonymous admin",
+			path:     "/admin",
+			wantCode: http.StatusUnauthorized,
+			wantBody: `{"error":"authentication required","requestId":"req-1"}`,
+		},
+		{
+			name:     "admin without role",
+			path:     "/admin",
+			header:   [2]string{auth.HeaderAPIKey, "client-key"},
+			wantCode: http.StatusForbidden,
+			wantBody: `{"error":"forbidden","requestId":"req-1"}`,
+		},
+		{
+			name:     "admin with role",
+			path:     "/admin",
+			header:   [2]string{auth.HeaderAPIKey, "admin-key"},
+			wantCode: http.StatusOK,
+			wantBody: "ops",
+		},
+		{
+			name:     "jwt scope",
+			path:     "/scoped",
+			header:   [2]string{"Authorization", "Bearer " + signHS256(t, hmacKey, map[string]any{"sub": "bob", "scope": "orders:write", "exp": expiry})},
+			wantCode: http.StatusOK,
+			wantBody: "bob",
+		},
+		{
+			name:     "jwt missing scope",
+			path:     "/scoped",
+			header:   [2]string{"Authorization", "Bearer " + signHS256(t, hmacKey, map[string]any{"sub": "bob", "exp": expiry})},
+			wantCode: http.StatusForbidden,
+			wantBody: `{"error":"forbidden","requestId":"req-1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			req.Header.Set(requestid.Header, "req-1")
+
+			if tt.header[0] != "" {
+				req.Header.Set(tt.header[0], tt.header[1])
+			}
+
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantCode, rec.Code)
+			require.Equal(t, tt.wantBody, string(trimNewline(rec.Body.Bytes())))
+		})
+	}
+}
+
+func TestMiddleware_logAttrs(t *testing.T) {
+	t.Parallel()
+
+	var got context.Context
+
+	a := auth.NewAPIKeyAuthenticator(map[string]auth.Principal{auth.HashAPIKey("k"): {Subject: "ops"}})
+	h := auth.Middleware(a)(func(w http.ResponseWriter, r *http.Request) error {
+		got = r.Context()
+
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(auth.HeaderAPIKey, "k")
+
+	require.NoError(t, h(httptest.NewRecorder(), req))
+
+	attrs := log.AttrsFromContext(got)
+	require.Len(t, attrs, 1)
+	require.Equal(t, "principal", attrs[0].Key)
+	require.Equal(t, "[subject=ops method=api_key]", attrs[0].Value.String())
+}
+
+func trimNewline(b []byte) []byte {
+	if n := len(b); n > 0 && b[n-1] == '\n' {
+		return b[:n-1]
+	}
+
+	return b
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+)
+
+type Principal struct {
+	Subject string
+	Method  string
+	Roles   []string
+	Scopes  []string
+}
+
+func (p Principal) HasRole(role string) bool {
+	return contains(p.Roles, role)
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+
+	return p, ok
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
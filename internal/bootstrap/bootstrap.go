@@ -0,0 +1,80 @@
+// Package bootstrap sets up what the entry points have in common: tracing,
+// logging and the order service.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/internal/adapters"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+)
+
+type Runtime struct {
+	TracerProvider *sdktrace.TracerProvider
+	Tracer         trace.Tracer
+	Logger         *log.SlogLogger
+	// LogLevel changes the level of Logger while it is in use.
+	LogLevel *slog.LevelVar
+}
+
+// Setup configures tracing and logging from the environment. Libraries logging
+// through log/slog are routed to the same output.
+func Setup(ctx context.Context, serviceName, tracerName string) (*Runtime, error) {
+	tracingConfig, err := tracing.FromEnv(serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("tracingFromEnv: %w", err)
+	}
+
+	tp, err := tracing.Setup(ctx, tracingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("setup: %w", err)
+	}
+
+	logLevel := new(slog.LevelVar)
+
+	logOpts, err := log.FromEnv(logLevel)
+	if err != nil {
+		return nil, fmt.Errorf("logFromEnv: %w", err)
+	}
+
+	logger := log.NewLogger(logOpts...)
+
+	slog.SetDefault(slog.New(log.NewHandler(logger)))
+
+	return &Runtime{
+		TracerProvider: tp,
+		Tracer:         tp.Tracer(tracerName),
+		Logger:         logger,
+		LogLevel:       logLevel,
+	}, nil
+}
+
+// OrderService traces the order service, its solver and repository.
+func (rt *Runtime) OrderService(repository domain.PackRepository, opts ...domain.OrderServiceOption) *adapters.TracedOrderService {
+	opts = append([]domain.OrderServiceOption{
+		domain.WithSolver(adapters.NewTracedSolver(domain.GreedySolver{Phase: adapters.TracePhases(rt.Tracer)}, rt.Tracer)),
+	}, opts...)
+
+	return adapters.NewTracedOrderService(domain.NewOrderService(
+		adapters.NewTracedPackRepository(repository, rt.Tracer),
+		opts...,
+	), rt.Tracer)
+}
+
+// LambdaHandler runs every invocation of fn in a "handler" span. The spans are
+// flushed before returning, as the environment may be frozen afterwards.
+func LambdaHandler[In, Out any](rt *Runtime, fn func(ctx context.Context, in In) (Out, error)) func(ctx context.Context, in In) (Out, error) {
+	return func(ctx context.Context, in In) (Out, error) {
+		defer rt.TracerProvider.ForceFlush(context.WithoutCancel(ctx))
+
+		ctx, span := rt.Tracer.Start(ctx, "handler")
+		defer span.End()
+
+		return fn(ctx, in)
+	}
+}
@@ -0,0 +1,36 @@
+package bootstrap_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/bootstrap"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestLambdaHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		exporter = tracetest.NewInMemoryExporter()
+		tp       = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+		rt       = &bootstrap.Runtime{TracerProvider: tp, Tracer: tp.Tracer("test")}
+	)
+
+	handler := bootstrap.LambdaHandler(rt, func(ctx context.Context, in int) (int, error) {
+		require.True(t, trace.SpanContextFromContext(ctx).IsValid())
+
+		return in * 2, nil
+	})
+
+	got, err := handler(context.Background(), 21)
+	require.NoError(t, err)
+	require.Equal(t, 42, got)
+
+	// flushed without waiting for the batch delay
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "handler", spans[0].Name)
+}
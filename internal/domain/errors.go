@@ -1,5 +1,22 @@
 package domain
 
-import "errors"
+import "github.com/vcraescu/gsh-assessment/pkg/errs"
 
-var ErrInvalidArgument = errors.New("invalid argument")
+var ErrInvalidArgument = &Error{code: "invalid_argument", msg: "invalid argument"}
+
+var _ errs.Coder = (*Error)(nil)
+
+// Error is a domain error identified by a code stable enough for clients and
+// log queries to rely on.
+type Error struct {
+	code string
+	msg  string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Code() string {
+	return e.code
+}
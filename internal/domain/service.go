@@ -2,62 +2,79 @@ package domain
 
 import (
 	"context"
-	"fmt"
-	"math"
-	"sort"
+	"github.com/vcraescu/gsh-assessment/pkg/errs"
+	"time"
 )
 
 type PackRepository interface {
 	FindAll(ctx context.Context) ([]Pack, error)
 }
 
+// OrderObserver is told about every order OrderService.Create solves.
+type OrderObserver interface {
+	ObserveOrder(ctx context.Context, quantity int, order Order, solveDuration time.Duration)
+}
+
+type OrderServiceOption func(s *OrderService)
+
+func WithOrderObserver(observer OrderObserver) OrderServiceOption {
+	return func(s *OrderService) {
+		s.observer = observer
+	}
+}
+
+// WithSolver replaces the default GreedySolver.
+func WithSolver(solver Solver) OrderServiceOption {
+	return func(s *OrderService) {
+		s.solver = solver
+	}
+}
+
 type OrderService struct {
 	repository PackRepository
+	solver     Solver
+	observer   OrderObserver
 }
 
-func NewOrderService(repository PackRepository) *OrderService {
-	return &OrderService{repository: repository}
+func NewOrderService(repository PackRepository, opts ...OrderServiceOption) *OrderService {
+	s := &OrderService{
+		repository: repository,
+		solver:     GreedySolver{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *OrderService) Create(ctx context.Context, quantity int) (Order, error) {
 	out := Order{}
 
 	if quantity <= 0 {
-		return out, fmt.Errorf("quantity must be greater than zero; got %v: %w", quantity, ErrInvalidArgument)
+		return out, errs.Wrapf(ErrInvalidArgument, "quantity must be greater than zero; got %v", quantity)
 	}
 
 	packs, err := s.repository.FindAll(ctx)
 	if err != nil {
-		return out, fmt.Errorf("findAll: %w", err)
+		return out, errs.Wrap(err, "findAll")
 	}
 
 	if len(packs) == 0 {
 		return out, nil
 	}
 
-	// sort packs descending
-	sort.Slice(packs, func(i, j int) bool {
-		return packs[i].Size > packs[j].Size
-	})
+	started := time.Now()
 
-	minPack := packs[len(packs)-1]
-	quantity = int(math.Ceil(float64(quantity)/float64(minPack.Size))) * minPack.Size
-
-	for _, pack := range packs {
-		if packQuantity := quantity / pack.Size; packQuantity > 0 {
-			quantity %= pack.Size
-
-			out.Rows = append(out.Rows, OrderRow{
-				Quantity: packQuantity,
-				Pack:     pack.Size,
-			})
-		}
+	out, err = s.solver.Solve(ctx, quantity, packs)
+	if err != nil {
+		return out, errs.Wrap(err, "solve")
 	}
 
-	// sort rows descending to ensure predictable output
-	sort.Slice(out.Rows, func(i, j int) bool {
-		return out.Rows[i].Pack > out.Rows[j].Pack
-	})
+	if s.observer != nil {
+		s.observer.ObserveOrder(ctx, quantity, out, time.Since(started))
+	}
 
 	return out, nil
 }
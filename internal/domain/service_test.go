@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vcraescu/gsh-assessment/internal/domain"
 	"testing"
+	"time"
 )
 
 var _ domain.PackRepository = (*PackRepository)(nil)
@@ -392,3 +393,59 @@ func TestOrderService_Create(t *testing.T) {
 		})
 	}
 }
+
+var _ domain.OrderObserver = (*OrderObserver)(nil)
+
+type OrderObserver struct {
+	mock.Mock
+}
+
+func (o *OrderObserver) ObserveOrder(ctx context.Context, quantity int, order domain.Order, solveDuration time.Duration) {
+	o.Called(ctx, quantity, order, solveDuration)
+}
+
+func TestOrderService_Create_observer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	repository := &PackRepository{}
+	repository.On("FindAll", ctx).Return([]domain.Pack{{Size: 250}, {Size: 500}}, nil)
+
+	want := domain.Order{Rows: []domain.OrderRow{{Quantity: 1, Pack: 500}}}
+
+	observer := &OrderObserver{}
+	observer.On("ObserveOrder", ctx, 251, want, mock.AnythingOfType("time.Duration")).Return()
+
+	got, err := domain.NewOrderService(repository, domain.WithOrderObserver(observer)).Create(ctx, 251)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Equal(t, 500, got.Items())
+	require.Equal(t, 1, got.Packs())
+
+	observer.AssertExpectations(t)
+}
+
+func TestPackSetVersion(t *testing.T) {
+	t.Parallel()
+
+	a := domain.PackSetVersion([]domain.Pack{{Size: 250}, {Size: 500}})
+	b := domain.PackSetVersion([]domain.Pack{{Size: 500}, {Size: 250}})
+	c := domain.PackSetVersion([]domain.Pack{{Size: 250}, {Size: 1000}})
+
+	require.Equal(t, a, b)
+	require.NotEqual(t, a, c)
+	require.Len(t, a, 12)
+}
+
+func TestGreedySolver_Solve(t *testing.T) {
+	t.Parallel()
+
+	packs := []domain.Pack{{Size: 250}, {Size: 500}, {Size: 1000}}
+
+	got, err := domain.GreedySolver{}.Solve(context.Background(), 1001, packs)
+	require.NoError(t, err)
+
+	require.Equal(t, domain.Order{Rows: []domain.OrderRow{{Quantity: 1, Pack: 1000}, {Quantity: 1, Pack: 250}}}, got)
+	require.Equal(t, []domain.Pack{{Size: 250}, {Size: 500}, {Size: 1000}}, packs, "packs must not be modified")
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// Solver chooses the packs which fulfil an order.
+type Solver interface {
+	// Strategy names the algorithm.
+	Strategy() string
+	// Solve must not modify packs.
+	Solve(ctx context.Context, quantity int, packs []Pack) (Order, error)
+}
+
+// PhaseFunc runs fn, the named phase of a solver. Decorators use it to
+// instrument the phases without the solver knowing about it.
+type PhaseFunc func(ctx context.Context, name string, fn func(ctx context.Context))
+
+var _ Solver = GreedySolver{}
+
+// GreedySolver rounds the quantity up to a multiple of the smallest pack and
+// fills it with the largest packs first.
+type GreedySolver struct {
+	// Phase, when set, runs each of the sortPacks, roundUp, fill and sortRows
+	// phases.
+	Phase PhaseFunc
+}
+
+func (GreedySolver) Strategy() string {
+	return "greedy"
+}
+
+func (s GreedySolver) Solve(ctx context.Context, quantity int, packs []Pack) (Order, error) {
+	out := Order{}
+
+	if len(packs) == 0 {
+		return out, nil
+	}
+
+	phase := s.Phase
+	if phase == nil {
+		phase = func(ctx context.Context, _ string, fn func(ctx context.Context)) { fn(ctx) }
+	}
+
+	phase(ctx, "sortPacks", func(context.Context) {
+		// sort packs descending
+		packs = append([]Pack(nil), packs...)
+		sort.Slice(packs, func(i, j int) bool {
+			return packs[i].Size > packs[j].Size
+		})
+	})
+
+	phase(ctx, "roundUp", func(context.Context) {
+		minPack := packs[len(packs)-1]
+		quantity = int(math.Ceil(float64(quantity)/float64(minPack.Size))) * minPack.Size
+	})
+
+	phase(ctx, "fill", func(context.Context) {
+		for _, pack := range packs {
+			if packQuantity := quantity / pack.Size; packQuantity > 0 {
+				quantity %= pack.Size
+
+				out.Rows = append(out.Rows, OrderRow{
+					Quantity: packQuantity,
+					Pack:     pack.Size,
+				})
+			}
+		}
+	})
+
+	phase(ctx, "sortRows", func(context.Context) {
+		// sort rows descending to ensure predictable output
+		sort.Slice(out.Rows, func(i, j int) bool {
+			return out.Rows[i].Pack > out.Rows[j].Pack
+		})
+	})
+
+	return out, nil
+}
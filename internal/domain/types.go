@@ -1,14 +1,61 @@
 package domain
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+)
+
 type OrderRow struct {
-	Quantity int `json:"quantity,omitempty"`
-	Pack     int `json:"pack,omitempty"`
+	Quantity int `json:"quantity,omitempty" xml:"quantity,omitempty"`
+	Pack     int `json:"pack,omitempty" xml:"pack,omitempty"`
 }
 
 type Order struct {
-	Rows []OrderRow `json:"rows,omitempty"`
+	Rows []OrderRow `json:"rows,omitempty" xml:"rows>row,omitempty"`
+}
+
+// Items is the number of items shipped, which may exceed the quantity ordered.
+func (o Order) Items() int {
+	var n int
+
+	for _, row := range o.Rows {
+		n += row.Quantity * row.Pack
+	}
+
+	return n
+}
+
+// Packs is the number of packs shipped.
+func (o Order) Packs() int {
+	var n int
+
+	for _, row := range o.Rows {
+		n += row.Quantity
+	}
+
+	return n
 }
 
 type Pack struct {
-	Size int `json:"size,omitempty"`
+	Size int `json:"size,omitempty" xml:"size,omitempty"`
+}
+
+// PackSetVersion identifies a set of pack sizes regardless of their order, so
+// orders solved with different configurations can be told apart.
+func PackSetVersion(packs []Pack) string {
+	sizes := make([]int, len(packs))
+	for i, pack := range packs {
+		sizes[i] = pack.Size
+	}
+
+	sort.Ints(sizes)
+
+	h := sha256.New()
+	for _, size := range sizes {
+		h.Write([]byte(strconv.Itoa(size) + ","))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:12]
 }
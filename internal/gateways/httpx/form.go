@@ -0,0 +1,148 @@
+package httpx
+
+import (
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// decodeValues maps query string or form values onto the fields of the struct
+// request points to. Fields are matched by their JSON name, so the same DTO
+// works for every transport.
+func decodeValues(values url.Values, request any) error {
+	v := reflect.ValueOf(request)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decodeValues: expected pointer to struct; got %T", request)
+	}
+
+	v = v.Elem()
+
+	fields := make(map[string]int, v.NumField())
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if name := validation.FieldName(field); name != "-" {
+			fields[name] = i
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	// predictable error order
+	sort.Strings(keys)
+
+	var errs validation.Errors
+
+	for _, key := range keys {
+		i, ok := fields[key]
+		if !ok {
+			errs = append(errs, validation.FieldError{Path: key, Message: "unknown field"})
+
+			continue
+		}
+
+		if msg, ok := setValue(v.Field(i), values[key]); !ok {
+			errs = append(errs, validation.FieldError{Path: key, Message: msg})
+		}
+	}
+
+	if len(errs) > 0 {
+		return invalidRequest(errs...)
+	}
+
+	return nil
+}
+
+func setValue(v reflect.Value, raw []string) (string, bool) {
+	if v.Kind() == reflect.Slice {
+		out := reflect.MakeSlice(v.Type(), len(raw), len(raw))
+
+		for i, s := range raw {
+			if msg, ok := setScalar(out.Index(i), s); !ok {
+				return msg, false
+			}
+		}
+
+		v.Set(out)
+
+		return "", true
+	}
+
+	if len(raw) != 1 {
+		return "must be a single value", false
+	}
+
+	return setScalar(v, raw[0])
+}
+
+func setScalar(v reflect.Value, s string) (string, bool) {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		if msg, ok := setScalar(ptr.Elem(), s); !ok {
+			return msg, false
+		}
+
+		v.Set(ptr)
+
+		return "", true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+		return "", true
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err == nil {
+			v.SetBool(b)
+
+			return "", true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err == nil {
+			v.SetInt(n)
+
+			return "", true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err == nil {
+			v.SetUint(n)
+
+			return "", true
+		}
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err == nil {
+			v.SetFloat(f)
+
+			return "", true
+		}
+	default:
+		return "is not supported in forms", false
+	}
+
+	return fmt.Sprintf("must be %s; got %q", jsonTypeName(v.Type()), s), false
+}
+
+func valuesPresent(values url.Values) validation.Present {
+	out := make(validation.Present, len(values))
+
+	for key := range values {
+		out[key] = true
+	}
+
+	return out
+}
@@ -1,14 +1,38 @@
 package httpx
 
 import (
+	"context"
+	"encoding/json"
+	"github.com/vcraescu/gsh-assessment/internal/health"
 	"github.com/vcraescu/gsh-assessment/internal/httpserver"
 	"net/http"
 )
 
-func NewHealthzCheckHandler() httpserver.HandlerFunc {
+// NewLivezHandler reports whether the process should be restarted.
+func NewLivezHandler(h *health.Health) httpserver.HandlerFunc {
+	return newHealthHandler(h.Live)
+}
+
+// NewReadyzHandler reports whether the service should receive traffic.
+func NewReadyzHandler(h *health.Health) httpserver.HandlerFunc {
+	return newHealthHandler(h.Ready)
+}
+
+// newHealthHandler answers 503 only when a critical check fails; a report
+// degraded by non-critical checks is still a 200.
+func newHealthHandler(probe func(ctx context.Context) health.Report) httpserver.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
-		w.WriteHeader(http.StatusOK)
+		report := probe(r.Context())
+
+		code := http.StatusOK
+		if report.Status == health.StatusFail {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", mimeJSON)
+		w.WriteHeader(code)
 
-		return nil
+		return json.NewEncoder(w).Encode(report)
 	}
 }
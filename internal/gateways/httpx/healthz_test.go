@@ -0,0 +1,81 @@
+package httpx_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/httpx"
+	"github.com/vcraescu/gsh-assessment/internal/health"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewReadyzHandler(t *testing.T) {
+	t.Parallel()
+
+	fail := func(context.Context) error { return errors.New("boom") }
+
+	tests := []struct {
+		name       string
+		setup      func(h *health.Health)
+		wantCode   int
+		wantStatus health.Status
+	}{
+		{
+			name:       "no checks",
+			setup:      func(h *health.Health) {},
+			wantCode:   http.StatusOK,
+			wantStatus: health.StatusPass,
+		},
+		{
+			name: "non-critical failure",
+			setup: func(h *health.Health) {
+				h.RegisterReadiness("cache", fail, health.WithSeverity(health.NonCritical))
+			},
+			wantCode:   http.StatusOK,
+			wantStatus: health.StatusWarn,
+		},
+		{
+			name: "draining",
+			setup: func(h *health.Health) {
+				readiness := httpserver.NewReadiness()
+				readiness.Drain()
+
+				h.RegisterReadiness("shutdown", readiness.Check)
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: health.StatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := health.New()
+			tt.setup(h)
+
+			w := httptest.NewRecorder()
+			err := httpx.NewReadyzHandler(h)(w, httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody))
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantCode, w.Code)
+			require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			require.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+			got := health.Report{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			require.Equal(t, tt.wantStatus, got.Status)
+
+			// liveness doesn't depend on readiness checks
+			w = httptest.NewRecorder()
+			require.NoError(t, httpx.NewLivezHandler(h)(w, httptest.NewRequest(http.MethodGet, "/livez", http.NoBody)))
+			require.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package httpx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type LogLevelRequest struct {
+	Level string `json:"level" validate:"required,oneof=debug info warn error"`
+}
+
+type LogLevelResponse struct {
+	XMLName xml.Name `json:"-" xml:"response"`
+	Level   string   `json:"level" xml:"level"`
+}
+
+func NewGetLogLevelHandler(level *slog.LevelVar) httpserver.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		return encodeLogLevel(w, r, level)
+	}
+}
+
+// NewSetLogLevelHandler changes the minimum level of every logger sharing
+// level, effective immediately.
+func NewSetLogLevelHandler(level *slog.LevelVar, logger log.Logger) httpserver.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		req := &LogLevelRequest{}
+
+		if err := decodeRequest(w, r, req); err != nil {
+			return handleError(err, w, r)
+		}
+
+		newLevel, err := log.ParseLevel(req.Level)
+		if err != nil {
+			return handleError(invalidRequest(), w, r)
+		}
+
+		oldLevel := level.Level()
+		level.Set(newLevel)
+
+		// logged as a warning so it's visible at any level
+		logger.Warn(r.Context(), "log level changed",
+			slog.String("from", levelName(oldLevel)),
+			slog.String("to", levelName(newLevel)),
+		)
+
+		return encodeLogLevel(w, r, level)
+	}
+}
+
+func encodeLogLevel(w http.ResponseWriter, r *http.Request, level *slog.LevelVar) error {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := httpserver.WriteResponse(w, r, http.StatusOK, LogLevelResponse{Level: levelName(level.Level())}); err != nil {
+		return fmt.Errorf("writeResponse: %w", err)
+	}
+
+	return nil
+}
+
+func levelName(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
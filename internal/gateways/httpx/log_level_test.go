@@ -0,0 +1,105 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/adapters"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/httpx"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogLevelRoutes(t *testing.T) {
+	t.Parallel()
+
+	repo, err := adapters.NewPackRepository()
+	require.NoError(t, err)
+
+	authenticator := auth.NewAPIKeyAuthenticator(map[string]auth.Principal{
+		auth.HashAPIKey("admin-key"): {Subject: "oncall", Roles: []string{"admin"}},
+		auth.HashAPIKey("user-key"):  {Subject: "user"},
+	})
+
+	var (
+		level = new(slog.LevelVar)
+		srv   = httpserver.New(log.NewNopLogger(), httpserver.Use(auth.Middleware(authenticator)))
+	)
+
+	httpx.RegisterRoutes(srv, domain.NewOrderService(repo), log.NewNopLogger(), httpx.WithLogLevel(level))
+
+	tests := []struct {
+		name      string
+		method    string
+		apiKey    string
+		body      string
+		wantCode  int
+		wantLevel string
+	}{
+		{
+			name:     "anonymous",
+			method:   http.MethodGet,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "not an admin",
+			method:   http.MethodPost,
+			apiKey:   "user-key",
+			body:     `{"level": "debug"}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:      "get",
+			method:    http.MethodGet,
+			apiKey:    "admin-key",
+			wantCode:  http.StatusOK,
+			wantLevel: "info",
+		},
+		{
+			name:     "unknown level",
+			method:   http.MethodPost,
+			apiKey:   "admin-key",
+			body:     `{"level": "loud"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:      "set",
+			method:    http.MethodPost,
+			apiKey:    "admin-key",
+			body:      `{"level": "debug"}`,
+			wantCode:  http.StatusOK,
+			wantLevel: "debug",
+		},
+	}
+
+	// the cases share the level, so they run in order
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/admin/log-level", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+
+		if tt.apiKey != "" {
+			req.Header.Set(auth.HeaderAPIKey, tt.apiKey)
+		}
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+
+		require.Equal(t, tt.wantCode, rec.Code, tt.name)
+
+		if tt.wantLevel == "" {
+			continue
+		}
+
+		got := httpx.LogLevelResponse{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got), tt.name)
+		require.Equal(t, tt.wantLevel, got.Level, tt.name)
+	}
+
+	require.Equal(t, slog.LevelDebug, level.Level())
+}
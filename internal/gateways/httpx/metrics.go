@@ -0,0 +1,17 @@
+package httpx
+
+import (
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/metrics"
+	"net/http"
+)
+
+func NewMetricsHandler(reg *metrics.Registry) httpserver.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Content-Type", metrics.ContentType)
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+
+		return reg.Write(w)
+	}
+}
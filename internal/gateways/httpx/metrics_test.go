@@ -0,0 +1,52 @@
+package httpx_test
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/adapters"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/httpx"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewMetricsHandler(t *testing.T) {
+	t.Parallel()
+
+	repo, err := adapters.NewPackRepository()
+	require.NoError(t, err)
+
+	var (
+		reg = metrics.NewRegistry()
+		svc = domain.NewOrderService(repo, domain.WithOrderObserver(adapters.NewOrderMetrics(reg)))
+		srv = httpserver.New(log.NewNopLogger(), httpserver.WithMetrics(reg))
+	)
+
+	httpx.RegisterRoutes(srv, svc, log.NewNopLogger(), httpx.WithMetrics(reg))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"quantity": 251}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, metrics.ContentType, rec.Header().Get("Content-Type"))
+
+	got := rec.Body.String()
+
+	require.Contains(t, got, `http_requests_total{method="POST",route="/orders",code="200"} 1`+"\n")
+	require.Contains(t, got, "order_requested_quantity_count 1\n")
+	require.Contains(t, got, "order_requested_quantity_sum 251\n")
+	require.Contains(t, got, "order_packs_sum 1\n")
+	require.Contains(t, got, "order_overshoot_items_sum 249\n")
+	require.Contains(t, got, "order_solver_duration_seconds_count 1\n")
+}
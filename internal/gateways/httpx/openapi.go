@@ -0,0 +1,37 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/openapi"
+	"net/http"
+	"sync"
+)
+
+// NewOpenAPIHandler serves the document of the routes registered on srv. It is
+// built on the first request, once every route is known.
+func NewOpenAPIHandler(srv httpserver.Server, info openapi.Info) httpserver.HandlerFunc {
+	var (
+		once sync.Once
+		doc  []byte
+		err  error
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) error {
+		once.Do(func() {
+			doc, err = json.Marshal(httpserver.OpenAPI(srv, info))
+		})
+
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+
+		w.Header().Set("Content-Type", mimeJSON)
+		w.WriteHeader(http.StatusOK)
+
+		_, err := w.Write(doc)
+
+		return err
+	}
+}
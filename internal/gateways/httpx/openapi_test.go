@@ -0,0 +1,49 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/adapters"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/httpx"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/openapi"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewOpenAPIHandler(t *testing.T) {
+	t.Parallel()
+
+	repo, err := adapters.NewPackRepository()
+	require.NoError(t, err)
+
+	srv := httpserver.New(log.NewNopLogger())
+	httpx.RegisterRoutes(srv, domain.NewOrderService(repo), log.NewNopLogger())
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", http.NoBody))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	got := &openapi.Document{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), got))
+
+	require.Equal(t, openapi.Version, got.OpenAPI)
+	require.NotNil(t, got.Paths["/orders"].Post)
+	require.NotNil(t, got.Paths["/orders"].Post.RequestBody.Content["application/x-www-form-urlencoded"])
+	require.Contains(t, got.Paths["/orders"].Post.Responses, "400")
+	require.NotNil(t, got.Paths["/orders/quote"].Get)
+	require.Len(t, got.Paths["/orders/quote"].Get.Parameters, 1)
+	require.Contains(t, got.Components.Schemas, "httpx.CreateOrderRequest")
+	require.Contains(t, got.Components.Schemas, "domain.Order")
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", http.NoBody))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `spec-url="/openapi.json"`)
+}
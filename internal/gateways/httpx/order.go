@@ -2,6 +2,7 @@ package httpx
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"github.com/vcraescu/gsh-assessment/internal/domain"
@@ -9,55 +10,101 @@ import (
 	"github.com/vcraescu/gsh-assessment/pkg/log"
 	"log/slog"
 	"net/http"
+	"strconv"
 )
 
+const quoteMaxAge = 300
+
 type OrderService interface {
 	Create(ctx context.Context, quantity int) (domain.Order, error)
 }
 
 type CreateOrderRequest struct {
-	Quantity int `json:"quantity"`
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+func (CreateOrderRequest) MaxBodyBytes() int64 {
+	return 1 << 10
 }
 
 type CreateOrderResponse struct {
-	Data domain.Order `json:"data"`
+	XMLName xml.Name     `json:"-" xml:"response"`
+	Data    domain.Order `json:"data" xml:"data"`
+}
+
+func (r CreateOrderResponse) MarshalCSV() [][]string {
+	out := [][]string{{"pack", "quantity"}}
+
+	for _, row := range r.Data.Rows {
+		out = append(out, []string{strconv.Itoa(row.Pack), strconv.Itoa(row.Quantity)})
+	}
+
+	return out
 }
 
 func NewCreateOrderHandler(svc OrderService, logger log.Logger) httpserver.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		req := &CreateOrderRequest{}
 
-		if err := decodeRequest(r, req); err != nil {
-			return handleError(err, w)
+		if err := decodeRequest(w, r, req); err != nil {
+			return handleError(err, w, r)
 		}
 
-		order, err := svc.Create(r.Context(), req.Quantity)
-		if err != nil {
-			logger.Error(r.Context(), "create order failed", slog.Any("payload", req), log.Error(err))
+		return createOrder(w, r, svc, logger, req)
+	}
+}
 
-			return handleError(err, w)
-		}
+// NewQuoteOrderHandler serves the same calculation as NewCreateOrderHandler
+// from the query string, so that responses can be cached by intermediaries.
+func NewQuoteOrderHandler(svc OrderService, logger log.Logger) httpserver.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		req := &CreateOrderRequest{}
 
-		if err := encodeResponse(w, http.StatusOK, CreateOrderResponse{Data: order}); err != nil {
-			return fmt.Errorf("encodeResponse: %w", err)
+		if err := decodeQuery(r, req); err != nil {
+			return handleError(err, w, r)
 		}
 
-		return nil
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(quoteMaxAge))
+
+		return createOrder(w, r, svc, logger, req)
 	}
 }
 
-func handleError(err error, w http.ResponseWriter) error {
-	resp := ErrorResponse{
-		Error: err.Error(),
+func createOrder(
+	w http.ResponseWriter, r *http.Request, svc OrderService, logger log.Logger, req *CreateOrderRequest,
+) error {
+	order, err := svc.Create(r.Context(), req.Quantity)
+	if err != nil {
+		logger.Error(r.Context(), "create order failed", slog.Any("payload", req), log.Error(err))
+
+		w.Header().Del("Cache-Control")
+
+		return handleError(err, w, r)
 	}
 
-	code := http.StatusInternalServerError
+	if err := httpserver.WriteResponse(w, r, http.StatusOK, CreateOrderResponse{Data: order}); err != nil {
+		return fmt.Errorf("writeResponse: %w", err)
+	}
+
+	return nil
+}
+
+func handleError(err error, w http.ResponseWriter, r *http.Request) error {
+	var (
+		code        = http.StatusInternalServerError
+		maxBytesErr *http.MaxBytesError
+	)
 
-	if errors.Is(err, domain.ErrInvalidArgument) {
+	switch {
+	case errors.As(err, &maxBytesErr):
+		code = http.StatusRequestEntityTooLarge
+	case errors.Is(err, errUnsupportedMediaType):
+		code = http.StatusUnsupportedMediaType
+	case errors.Is(err, domain.ErrInvalidArgument):
 		code = http.StatusBadRequest
 	}
 
-	if err := encodeResponse(w, code, resp); err != nil {
+	if err := httpserver.WriteError(w, r, code, err); err != nil {
 		return fmt.Errorf("encode error response: %w", err)
 	}
 
@@ -8,10 +8,14 @@ import (
 	"github.com/vcraescu/gsh-assessment/internal/adapters"
 	"github.com/vcraescu/gsh-assessment/internal/domain"
 	"github.com/vcraescu/gsh-assessment/internal/gateways/httpx"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
 	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -35,7 +39,10 @@ func TestNewCreateOrderHandler(t *testing.T) {
 				req: newRequest(t, "test"),
 			},
 			wantStatusCode: http.StatusBadRequest,
-			wantBody:       marshalJSON(t, httpx.ErrorResponse{Error: domain.ErrInvalidArgument.Error()}),
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: must be an object; got string",
+				Fields: []validation.FieldError{{Message: "must be an object; got string"}},
+			}),
 		},
 		{
 			name: "empty request",
@@ -43,8 +50,172 @@ func TestNewCreateOrderHandler(t *testing.T) {
 				req: newRequest(t, nil),
 			},
 			wantStatusCode: http.StatusBadRequest,
-			wantBody: marshalJSON(t, httpx.ErrorResponse{
-				Error: "quantity must be greater than zero; got 0: invalid argument",
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: request body is empty",
+				Fields: []validation.FieldError{{Message: "request body is empty"}},
+			}),
+		},
+		{
+			name: "malformed json",
+			args: args{
+				req: newRawRequest(t, `{"quantity": 1`),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: malformed JSON",
+				Fields: []validation.FieldError{{Message: "malformed JSON"}},
+			}),
+		},
+		{
+			name: "quantity as string",
+			args: args{
+				req: newRawRequest(t, `{"quantity": "250"}`),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: quantity: must be an integer; got string",
+				Fields: []validation.FieldError{{Path: "quantity", Message: "must be an integer; got string"}},
+			}),
+		},
+		{
+			name: "unknown field",
+			args: args{
+				req: newRawRequest(t, `{"quantity": 250, "qty": 1}`),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: qty: unknown field",
+				Fields: []validation.FieldError{{Path: "qty", Message: "unknown field"}},
+			}),
+		},
+		{
+			name: "missing quantity",
+			args: args{
+				req: newRawRequest(t, `{}`),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: quantity: is required",
+				Fields: []validation.FieldError{{Path: "quantity", Message: "is required"}},
+			}),
+		},
+		{
+			name: "zero quantity",
+			args: args{
+				req: newRawRequest(t, `{"quantity": 0}`),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: quantity: must be greater than or equal to 1; got 0",
+				Fields: []validation.FieldError{{Path: "quantity", Message: "must be greater than or equal to 1; got 0"}},
+			}),
+		},
+		{
+			name: "negative quantity",
+			args: args{
+				req: newRawRequest(t, `{"quantity": -5}`),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: quantity: must be greater than or equal to 1; got -5",
+				Fields: []validation.FieldError{{Path: "quantity", Message: "must be greater than or equal to 1; got -5"}},
+			}),
+		},
+		{
+			name: "body too large",
+			args: args{
+				req: newRawRequest(t, `{"quantity": 250`+strings.Repeat(" ", 2048)+`}`),
+			},
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error: "request body exceeds 1024 bytes: http: request body too large",
+			}),
+		},
+		{
+			name: "unsupported media type",
+			args: args{
+				req: withHeader(newRawRequest(t, `quantity: 250`), "Content-Type", "text/yaml"),
+			},
+			wantStatusCode: http.StatusUnsupportedMediaType,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error: "unsupported media type: text/yaml",
+			}),
+		},
+		{
+			name: "form post",
+			args: args{
+				req: withHeader(newRawRequest(t, "quantity=251"), "Content-Type", "application/x-www-form-urlencoded"),
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: marshalJSON(t, httpx.CreateOrderResponse{
+				Data: domain.Order{
+					Rows: []domain.OrderRow{
+						{
+							Quantity: 1,
+							Pack:     500,
+						},
+					},
+				},
+			}),
+		},
+		{
+			name: "invalid form post",
+			args: args{
+				req: withHeader(newRawRequest(t, "quantity=abc"), "Content-Type", "application/x-www-form-urlencoded"),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  `invalid argument: quantity: must be an integer; got "abc"`,
+				Fields: []validation.FieldError{{Path: "quantity", Message: `must be an integer; got "abc"`}},
+			}),
+		},
+		{
+			name: "csv",
+			args: args{
+				req: withHeader(newRequest(t, httpx.CreateOrderRequest{Quantity: 501}), "Accept", "text/csv"),
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       []byte("pack,quantity\n500,1\n250,1"),
+		},
+		{
+			name: "xml",
+			args: args{
+				req: withHeader(newRequest(t, httpx.CreateOrderRequest{Quantity: 251}), "Accept", "text/html, application/xml;q=0.9"),
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: []byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><data><rows><row><quantity>1</quantity><pack>500</pack></row></rows></data></response>`),
+		},
+		{
+			name: "xml error",
+			args: args{
+				req: withHeader(newRawRequest(t, `{}`), "Accept", "application/xml"),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: []byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><error>invalid argument: quantity: is required</error>` +
+				`<fields><field><path>quantity</path><message>is required</message></field></fields></response>`),
+		},
+		{
+			name: "not acceptable",
+			args: args{
+				req: withHeader(newRequest(t, httpx.CreateOrderRequest{Quantity: 251}), "Accept", "text/html"),
+			},
+			wantStatusCode: http.StatusNotAcceptable,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error: "not acceptable: supported media types are application/json, application/xml, text/xml, text/csv",
+			}),
+		},
+		{
+			name: "error with request id",
+			args: args{
+				req: withRequestID(newRawRequest(t, `{}`), "req-1"),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:     "invalid argument: quantity: is required",
+				Fields:    []validation.FieldError{{Path: "quantity", Message: "is required"}},
+				RequestID: "req-1",
 			}),
 		},
 		{
@@ -111,6 +282,22 @@ func newRequest(t *testing.T, body any) *http.Request {
 	return httptest.NewRequest(http.MethodGet, "http://example.com/test", buf)
 }
 
+func withHeader(req *http.Request, key, value string) *http.Request {
+	req.Header.Set(key, value)
+
+	return req
+}
+
+func withRequestID(req *http.Request, id string) *http.Request {
+	return req.WithContext(requestid.NewContext(req.Context(), id))
+}
+
+func newRawRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+
+	return httptest.NewRequest(http.MethodPost, "http://example.com/test", strings.NewReader(body))
+}
+
 func readBody(t *testing.T, resp *http.Response) []byte {
 	t.Helper()
 
@@ -128,3 +315,105 @@ func marshalJSON(t *testing.T, v any) []byte {
 
 	return b
 }
+
+func TestNewQuoteOrderHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		query            string
+		accept           string
+		wantStatusCode   int
+		wantCacheControl string
+		wantBody         []byte
+	}{
+		{
+			name:           "missing quantity",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: quantity: is required",
+				Fields: []validation.FieldError{{Path: "quantity", Message: "is required"}},
+			}),
+		},
+		{
+			name:           "unknown parameter",
+			query:          "quantity=1&size=2",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: size: unknown field",
+				Fields: []validation.FieldError{{Path: "size", Message: "unknown field"}},
+			}),
+		},
+		{
+			name:           "repeated parameter",
+			query:          "quantity=1&quantity=2",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody: marshalJSON(t, httpserver.ErrorResponse{
+				Error:  "invalid argument: quantity: must be a single value",
+				Fields: []validation.FieldError{{Path: "quantity", Message: "must be a single value"}},
+			}),
+		},
+		{
+			name:             "success",
+			query:            "quantity=12001",
+			wantStatusCode:   http.StatusOK,
+			wantCacheControl: "public, max-age=300",
+			wantBody: marshalJSON(t, httpx.CreateOrderResponse{
+				Data: domain.Order{
+					Rows: []domain.OrderRow{
+						{
+							Quantity: 2,
+							Pack:     5000,
+						},
+						{
+							Quantity: 1,
+							Pack:     2000,
+						},
+						{
+							Quantity: 1,
+							Pack:     250,
+						},
+					},
+				},
+			}),
+		},
+		{
+			name:             "success csv",
+			query:            "quantity=1",
+			accept:           "text/csv",
+			wantStatusCode:   http.StatusOK,
+			wantCacheControl: "public, max-age=300",
+			wantBody:         []byte("pack,quantity\n250,1"),
+		},
+	}
+
+	logger := log.NewNopLogger()
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo, err := adapters.NewPackRepository()
+			require.NoError(t, err)
+
+			h := httpx.NewQuoteOrderHandler(domain.NewOrderService(repo), logger)
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/orders/quote?"+tt.query, http.NoBody)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rec := httptest.NewRecorder()
+			require.NoError(t, h(rec, req))
+
+			got := rec.Result()
+
+			require.Equal(t, tt.wantStatusCode, got.StatusCode)
+			require.Equal(t, tt.wantCacheControl, got.Header.Get("Cache-Control"))
+			require.Equal(t, "Accept", got.Header.Get("Vary"))
+			require.Equal(t, string(tt.wantBody), string(readBody(t, got)))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package httpx
+
+import (
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"github.com/vcraescu/gsh-assessment/internal/health"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/internal/idempotency"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/metrics"
+	"github.com/vcraescu/gsh-assessment/pkg/openapi"
+	"github.com/vcraescu/gsh-assessment/web"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	apiVersion = "1.0.0"
+	adminRole  = "admin"
+)
+
+var (
+	requestMediaTypes  = []string{mimeJSON, mimeForm}
+	responseMediaTypes = []string{mimeJSON, mimeXML, mimeCSV}
+)
+
+type routesConfig struct {
+	middlewares map[string][]httpserver.Middleware
+	health      *health.Health
+	metrics     *metrics.Registry
+	logLevel    *slog.LevelVar
+}
+
+func (c *routesConfig) middleware(method, pattern string) httpserver.RouteOption {
+	return httpserver.WithMiddleware(c.middlewares[method+" "+pattern]...)
+}
+
+type RoutesOption func(c *routesConfig)
+
+// WithRouteMiddleware attaches middlewares to a single route, e.g. a rate
+// limiter to "POST", "/orders".
+func WithRouteMiddleware(method, pattern string, mws ...httpserver.Middleware) RoutesOption {
+	return func(c *routesConfig) {
+		key := method + " " + pattern
+		c.middlewares[key] = append(c.middlewares[key], mws...)
+	}
+}
+
+// WithIdempotency makes POST /orders honour the Idempotency-Key header.
+func WithIdempotency(store idempotency.Store, ttl time.Duration) RoutesOption {
+	return WithRouteMiddleware(http.MethodPost, "/orders", idempotency.Middleware(store, ttl))
+}
+
+// WithHealth serves the checks registered on h at /livez and /readyz.
+// Without it both endpoints report healthy.
+func WithHealth(h *health.Health) RoutesOption {
+	return func(c *routesConfig) {
+		c.health = h
+	}
+}
+
+// WithMetrics serves reg at /metrics in the Prometheus text format.
+func WithMetrics(reg *metrics.Registry) RoutesOption {
+	return func(c *routesConfig) {
+		c.metrics = reg
+	}
+}
+
+// WithLogLevel serves /admin/log-level to read and change level at runtime.
+// Only principals with the admin role may use it.
+func WithLogLevel(level *slog.LevelVar) RoutesOption {
+	return func(c *routesConfig) {
+		c.logLevel = level
+	}
+}
+
+func RegisterRoutes(srv httpserver.Server, svc OrderService, logger log.Logger, opts ...RoutesOption) {
+	cfg := &routesConfig{
+		middlewares: make(map[string][]httpserver.Middleware),
+		health:      health.New(),
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	srv.Get("/", web.StaticHandler, cfg.middleware(http.MethodGet, "/"))
+	srv.Post("/orders", NewCreateOrderHandler(svc, logger),
+		cfg.middleware(http.MethodPost, "/orders"),
+		httpserver.WithSummary("Calculate the packs needed to fulfil an order"),
+		httpserver.WithRequest(CreateOrderRequest{}, requestMediaTypes...),
+		httpserver.WithResponse(http.StatusOK, CreateOrderResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusBadRequest, httpserver.ErrorResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusRequestEntityTooLarge, httpserver.ErrorResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusUnsupportedMediaType, httpserver.ErrorResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusNotAcceptable, httpserver.ErrorResponse{}),
+		httpserver.WithError(http.StatusConflict, httpserver.ErrorResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusUnprocessableEntity, httpserver.ErrorResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusTooManyRequests, httpserver.ErrorResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusInternalServerError, httpserver.ErrorResponse{}, responseMediaTypes...),
+	)
+	srv.Get("/orders/quote", NewQuoteOrderHandler(svc, logger),
+		cfg.middleware(http.MethodGet, "/orders/quote"),
+		httpserver.WithSummary("Calculate the packs needed to fulfil an order; cacheable"),
+		httpserver.WithQuery(CreateOrderRequest{}),
+		httpserver.WithResponse(http.StatusOK, CreateOrderResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusBadRequest, httpserver.ErrorResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusNotAcceptable, httpserver.ErrorResponse{}),
+		httpserver.WithError(http.StatusTooManyRequests, httpserver.ErrorResponse{}, responseMediaTypes...),
+		httpserver.WithError(http.StatusInternalServerError, httpserver.ErrorResponse{}, responseMediaTypes...),
+	)
+	srv.Get("/livez", NewLivezHandler(cfg.health),
+		cfg.middleware(http.MethodGet, "/livez"),
+		httpserver.WithSummary("Liveness check"),
+		httpserver.WithResponse(http.StatusOK, health.Report{}),
+		httpserver.WithError(http.StatusServiceUnavailable, health.Report{}),
+	)
+	srv.Get("/readyz", NewReadyzHandler(cfg.health),
+		cfg.middleware(http.MethodGet, "/readyz"),
+		httpserver.WithSummary("Readiness check"),
+		httpserver.WithResponse(http.StatusOK, health.Report{}),
+		httpserver.WithError(http.StatusServiceUnavailable, health.Report{}),
+	)
+	// kept for probes configured before /livez existed
+	srv.Get("/healthz", NewLivezHandler(cfg.health),
+		cfg.middleware(http.MethodGet, "/healthz"),
+		httpserver.WithSummary("Liveness check; alias of /livez"),
+		httpserver.WithResponse(http.StatusOK, health.Report{}),
+		httpserver.WithError(http.StatusServiceUnavailable, health.Report{}),
+	)
+	if cfg.metrics != nil {
+		srv.Get("/metrics", NewMetricsHandler(cfg.metrics),
+			cfg.middleware(http.MethodGet, "/metrics"),
+			httpserver.WithSummary("Prometheus metrics"),
+			httpserver.WithResponse(http.StatusOK, nil, "text/plain"),
+		)
+	}
+
+	if cfg.logLevel != nil {
+		admin := httpserver.Group(srv, httpserver.WithMiddleware(auth.RequireRoles(adminRole)))
+
+		admin.Get("/admin/log-level", NewGetLogLevelHandler(cfg.logLevel),
+			cfg.middleware(http.MethodGet, "/admin/log-level"),
+			httpserver.WithSummary("Current log level"),
+			httpserver.WithResponse(http.StatusOK, LogLevelResponse{}),
+			httpserver.WithError(http.StatusUnauthorized, httpserver.ErrorResponse{}, responseMediaTypes...),
+			httpserver.WithError(http.StatusForbidden, httpserver.ErrorResponse{}, responseMediaTypes...),
+		)
+		admin.Post("/admin/log-level", NewSetLogLevelHandler(cfg.logLevel, logger),
+			cfg.middleware(http.MethodPost, "/admin/log-level"),
+			httpserver.WithSummary("Change the log level without a restart"),
+			httpserver.WithRequest(LogLevelRequest{}),
+			httpserver.WithResponse(http.StatusOK, LogLevelResponse{}),
+			httpserver.WithError(http.StatusBadRequest, httpserver.ErrorResponse{}),
+			httpserver.WithError(http.StatusUnauthorized, httpserver.ErrorResponse{}, responseMediaTypes...),
+			httpserver.WithError(http.StatusForbidden, httpserver.ErrorResponse{}, responseMediaTypes...),
+		)
+	}
+
+	srv.Get("/openapi.json", NewOpenAPIHandler(srv, openapi.Info{
+		Title:   "Packages Calculator",
+		Version: apiVersion,
+	}),
+		cfg.middleware(http.MethodGet, "/openapi.json"),
+		httpserver.WithSummary("OpenAPI document"),
+	)
+	srv.Get("/docs", web.DocsHandler,
+		cfg.middleware(http.MethodGet, "/docs"),
+		httpserver.WithSummary("API reference"),
+	)
+}
@@ -1,39 +1,190 @@
 package httpx
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
 	"io"
+	"mime"
 	"net/http"
+	"reflect"
+	"strings"
 )
 
-type ErrorResponse struct {
-	Error string `json:"error,omitempty"`
+const (
+	defaultMaxBodyBytes = 1 << 20
+
+	mimeJSON = "application/json"
+	mimeXML  = "application/xml"
+	mimeCSV  = "text/csv"
+	mimeForm = "application/x-www-form-urlencoded"
+)
+
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// bodyLimiter is implemented by request DTOs that need a body size limit other
+// than defaultMaxBodyBytes.
+type bodyLimiter interface {
+	MaxBodyBytes() int64
 }
 
-func decodeRequest(r *http.Request, request any) error {
-	if r.Body == nil {
-		return nil
+func decodeRequest(w http.ResponseWriter, r *http.Request, request any) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return invalidRequest(validation.FieldError{Message: "request body is empty"})
+	}
+
+	var mediaType string
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		var err error
+
+		if mediaType, _, err = mime.ParseMediaType(ct); err != nil {
+			return fmt.Errorf("%w: %s", errUnsupportedMediaType, ct)
+		}
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
-		if !errors.Is(err, io.EOF) {
-			return domain.ErrInvalidArgument
+	limit := int64(defaultMaxBodyBytes)
+	if l, ok := request.(bodyLimiter); ok {
+		limit = l.MaxBodyBytes()
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+	var present validation.Present
+
+	switch mediaType {
+	case "", mimeJSON:
+		var err error
+
+		if present, err = decodeJSON(r.Body, request); err != nil {
+			return err
+		}
+	case mimeForm:
+		if err := r.ParseForm(); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return fmt.Errorf("request body exceeds %d bytes: %w", maxBytesErr.Limit, err)
+			}
+
+			return invalidRequest(validation.FieldError{Message: "malformed form"})
+		}
+
+		if err := decodeValues(r.PostForm, request); err != nil {
+			return err
 		}
+
+		present = valuesPresent(r.PostForm)
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedMediaType, mediaType)
+	}
+
+	if err := validation.Validate(request, validation.WithPresent(present)); err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrInvalidArgument, err)
 	}
 
 	return nil
 }
 
-func encodeResponse(w http.ResponseWriter, code int, resp any) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
+func decodeQuery(r *http.Request, request any) error {
+	query := r.URL.Query()
 
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		return fmt.Errorf("marshal: %w", err)
+	if err := decodeValues(query, request); err != nil {
+		return err
+	}
+
+	if err := validation.Validate(request, validation.WithPresent(valuesPresent(query))); err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrInvalidArgument, err)
 	}
 
 	return nil
 }
+
+// decodeJSON decodes body into request and returns the paths the client sent,
+// so "required" can tell a missing member from a zero one.
+func decodeJSON(body io.Reader, request any) (validation.Present, error) {
+	var raw json.RawMessage
+
+	dec := json.NewDecoder(body)
+
+	if err := dec.Decode(&raw); err != nil {
+		return nil, decodeError(err)
+	}
+
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return nil, invalidRequest(validation.FieldError{Message: "request body must contain a single JSON value"})
+	}
+
+	dec = json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(request); err != nil {
+		return nil, decodeError(err)
+	}
+
+	present, err := validation.JSONPresent(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidArgument, err)
+	}
+
+	return present, nil
+}
+
+func decodeError(err error) error {
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		maxBytesErr *http.MaxBytesError
+	)
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return invalidRequest(validation.FieldError{Message: "request body is empty"})
+	case errors.As(err, &maxBytesErr):
+		return fmt.Errorf("request body exceeds %d bytes: %w", maxBytesErr.Limit, err)
+	case errors.As(err, &syntaxErr):
+		return invalidRequest(validation.FieldError{
+			Message: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset),
+		})
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return invalidRequest(validation.FieldError{Message: "malformed JSON"})
+	case errors.As(err, &typeErr):
+		return invalidRequest(validation.FieldError{
+			Path:    typeErr.Field,
+			Message: fmt.Sprintf("must be %s; got %s", jsonTypeName(typeErr.Type), typeErr.Value),
+		})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json doesn't export a type for this one
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+
+		return invalidRequest(validation.FieldError{Path: field, Message: "unknown field"})
+	}
+
+	return fmt.Errorf("%w: %w", domain.ErrInvalidArgument, err)
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+
+	return t.String()
+}
+
+func invalidRequest(errs ...validation.FieldError) error {
+	return fmt.Errorf("%w: %w", domain.ErrInvalidArgument, validation.Errors(errs))
+}
@@ -0,0 +1,87 @@
+// Package lambdax serves Lambda events with an http.Handler in process.
+package lambdax
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda"
+	"net/http"
+	"strings"
+)
+
+var ErrUnsupportedEvent = errors.New("unsupported event")
+
+var _ lambda.Handler = (*Adapter)(nil)
+
+type Adapter struct {
+	handler http.Handler
+}
+
+func NewAdapter(handler http.Handler) *Adapter {
+	return &Adapter{handler: handler}
+}
+
+// eventProbe holds the fields telling the event sources apart.
+type eventProbe struct {
+	Version        string `json:"version"`
+	HTTPMethod     string `json:"httpMethod"`
+	RequestContext struct {
+		ELB        json.RawMessage `json:"elb"`
+		DomainName string          `json:"domainName"`
+	} `json:"requestContext"`
+}
+
+// Invoke serves API Gateway REST (1.0) and HTTP (2.0) API, Application Load
+// Balancer and function URL events, whichever payload is received:
+//
+//	lambda.Start(lambdax.NewAdapter(srv))
+func (a *Adapter) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var probe eventProbe
+
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	var (
+		resp any
+		err  error
+	)
+
+	switch {
+	case len(probe.RequestContext.ELB) > 0:
+		resp, err = invoke(ctx, payload, a.ProxyALB)
+	case probe.Version == "2.0" && strings.Contains(probe.RequestContext.DomainName, ".lambda-url."):
+		resp, err = invoke(ctx, payload, a.ProxyFunctionURL)
+	case probe.Version == "2.0":
+		resp, err = invoke(ctx, payload, a.ProxyV2)
+	case probe.HTTPMethod != "":
+		resp, err = invoke(ctx, payload, a.ProxyV1)
+	default:
+		return nil, ErrUnsupportedEvent
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	return out, nil
+}
+
+func invoke[In, Out any](ctx context.Context, payload []byte, proxy func(context.Context, In) (Out, error)) (Out, error) {
+	var in In
+
+	if err := json.Unmarshal(payload, &in); err != nil {
+		var out Out
+
+		return out, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return proxy(ctx, in)
+}
@@ -0,0 +1,125 @@
+package lambdax_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/lambdax"
+	"net/http"
+	"testing"
+)
+
+func TestAdapter_Invoke(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		payload     string
+		wantContext func(t *testing.T, r *http.Request)
+		want        string
+	}{
+		{
+			name: "http api",
+			payload: `{
+				"version": "2.0",
+				"rawPath": "/orders/quote",
+				"rawQueryString": "quantity=1",
+				"headers": {"accept": "application/json"},
+				"requestContext": {
+					"domainName": "abc.execute-api.us-east-1.amazonaws.com",
+					"http": {"method": "GET", "sourceIp": "203.0.113.5"}
+				}
+			}`,
+			wantContext: func(t *testing.T, r *http.Request) {
+				_, ok := lambdax.RequestContextV2FromContext(r.Context())
+				require.True(t, ok)
+			},
+			want: `{"statusCode":200,"headers":{"Content-Type":"text/plain","X-Trace":"a,b"},"multiValueHeaders":null,"body":"GET /orders/quote?quantity=1 from 203.0.113.5:0","cookies":["session=abc"]}`,
+		},
+		{
+			name: "function url",
+			payload: `{
+				"version": "2.0",
+				"rawPath": "/orders/quote",
+				"rawQueryString": "quantity=1",
+				"requestContext": {
+					"domainName": "abc.lambda-url.us-east-1.on.aws",
+					"http": {"method": "GET", "sourceIp": "203.0.113.5"}
+				}
+			}`,
+			wantContext: func(t *testing.T, r *http.Request) {
+				_, ok := lambdax.RequestContextFunctionURLFromContext(r.Context())
+				require.True(t, ok)
+			},
+			want: `{"statusCode":200,"headers":{"Content-Type":"text/plain","X-Trace":"a,b"},"body":"GET /orders/quote?quantity=1 from 203.0.113.5:0","isBase64Encoded":false,"cookies":["session=abc"]}`,
+		},
+		{
+			name: "rest api",
+			payload: `{
+				"httpMethod": "GET",
+				"path": "/orders/quote",
+				"multiValueQueryStringParameters": {"quantity": ["1"]},
+				"requestContext": {"stage": "dev", "identity": {"sourceIp": "203.0.113.5"}}
+			}`,
+			wantContext: func(t *testing.T, r *http.Request) {
+				rc, ok := lambdax.RequestContextV1FromContext(r.Context())
+				require.True(t, ok)
+				require.Equal(t, "dev", rc.Stage)
+			},
+			want: `{"statusCode":200,"headers":null,"multiValueHeaders":{"Content-Type":["text/plain"],"Set-Cookie":["session=abc"],"X-Trace":["a","b"]},"body":"GET /orders/quote?quantity=1 from 203.0.113.5:0"}`,
+		},
+		{
+			name: "load balancer",
+			payload: `{
+				"httpMethod": "GET",
+				"path": "/orders/quote",
+				"queryStringParameters": {"quantity": "1"},
+				"headers": {"x-forwarded-for": "203.0.113.5"},
+				"requestContext": {"elb": {"targetGroupArn": "arn:aws:elasticloadbalancing:tg"}}
+			}`,
+			wantContext: func(t *testing.T, r *http.Request) {
+				rc, ok := lambdax.RequestContextALBFromContext(r.Context())
+				require.True(t, ok)
+				require.Equal(t, "arn:aws:elasticloadbalancing:tg", rc.ELB.TargetGroupArn)
+			},
+			want: `{"statusCode":200,"statusDescription":"200 OK","headers":{"Content-Type":"text/plain","Set-Cookie":"session=abc","X-Trace":"a,b"},"multiValueHeaders":null,"body":"GET /orders/quote?quantity=1 from 203.0.113.5:0","isBase64Encoded":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adapter := lambdax.NewAdapter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.wantContext(t, r)
+
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Add("X-Trace", "a")
+				w.Header().Add("X-Trace", "b")
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+				_, _ = w.Write([]byte(r.Method + " " + r.RequestURI + " from " + r.RemoteAddr))
+			}))
+
+			got, err := adapter.Invoke(context.Background(), []byte(tt.payload))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestAdapter_Invoke_unsupportedEvent(t *testing.T) {
+	t.Parallel()
+
+	adapter := lambdax.NewAdapter(http.NotFoundHandler())
+
+	_, err := adapter.Invoke(context.Background(), []byte(`{"Records": []}`))
+	require.ErrorIs(t, err, lambdax.ErrUnsupportedEvent)
+
+	_, err = adapter.Invoke(context.Background(), []byte(`not json`))
+	require.Error(t, err)
+
+	var syntaxErr *json.SyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+}
@@ -0,0 +1,80 @@
+package lambdax
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProxyALB serves an Application Load Balancer target group event. The
+// response uses multi-value headers when the request did, which is when they
+// are enabled on the target group.
+func (a *Adapter) ProxyALB(ctx context.Context, in events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+	req, err := newALBRequest(ctx, in)
+	if err != nil {
+		return events.ALBTargetGroupResponse{}, fmt.Errorf("newALBRequest: %w", err)
+	}
+
+	w := newResponseWriter()
+	a.handler.ServeHTTP(w, req)
+
+	out := events.ALBTargetGroupResponse{
+		StatusCode:        w.code,
+		StatusDescription: strconv.Itoa(w.code) + " " + http.StatusText(w.code),
+	}
+
+	if in.MultiValueHeaders != nil {
+		out.MultiValueHeaders = w.header
+	} else {
+		// only the last cookie can be set without multi-value headers
+		out.Headers = joinHeader(w.header, true)
+	}
+
+	out.Body, out.IsBase64Encoded = encodeBody(w.header, w.body.Bytes())
+
+	return out, nil
+}
+
+// newALBRequest takes the client address from the last X-Forwarded-For entry,
+// added by the load balancer. The path and query parameters are passed on as
+// received, not decoded.
+func newALBRequest(ctx context.Context, in events.ALBTargetGroupRequest) (*http.Request, error) {
+	var (
+		header = singleValueHeader(in.Headers)
+		query  []string
+	)
+
+	if in.MultiValueHeaders != nil {
+		header = multiValueHeader(in.MultiValueHeaders)
+
+		for key, values := range in.MultiValueQueryStringParameters {
+			for _, value := range values {
+				query = append(query, key+"="+value)
+			}
+		}
+	} else {
+		for key, value := range in.QueryStringParameters {
+			query = append(query, key+"="+value)
+		}
+	}
+
+	var sourceIP string
+
+	if forwarded := header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		addrs := strings.Split(forwarded[len(forwarded)-1], ",")
+		sourceIP = strings.TrimSpace(addrs[len(addrs)-1])
+	}
+
+	return request{
+		method:   in.HTTPMethod,
+		rawPath:  in.Path,
+		rawQuery: strings.Join(query, "&"),
+		header:   header,
+		body:     in.Body,
+		isBase64: in.IsBase64Encoded,
+		sourceIP: sourceIP,
+	}.httpRequest(newContextALB(ctx, in.RequestContext))
+}
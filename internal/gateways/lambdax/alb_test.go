@@ -0,0 +1,52 @@
+package lambdax_test
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/lambdax"
+	"net/http"
+	"testing"
+)
+
+func TestAdapter_ProxyALB_multiValueHeaders(t *testing.T) {
+	t.Parallel()
+
+	var got *http.Request
+
+	adapter := lambdax.NewAdapter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+
+		http.SetCookie(w, &http.Cookie{Name: "a", Value: "1"})
+		http.SetCookie(w, &http.Cookie{Name: "b", Value: "2"})
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	resp, err := adapter.ProxyALB(context.Background(), events.ALBTargetGroupRequest{
+		HTTPMethod: http.MethodGet,
+		Path:       "/orders/a%20b",
+		MultiValueQueryStringParameters: map[string][]string{
+			"quantity": {"1"},
+			"q":        {"a%20b"},
+		},
+		MultiValueHeaders: map[string][]string{
+			"accept":          {"text/csv", "application/json"},
+			"host":            {"orders.internal"},
+			"x-forwarded-for": {"198.51.100.1", "203.0.113.5, 10.0.0.1"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "/orders/a b", got.URL.Path)
+	require.Equal(t, "/orders/a%20b", got.URL.EscapedPath())
+	require.Equal(t, []string{"text/csv", "application/json"}, got.Header.Values("Accept"))
+	require.Equal(t, "a b", got.URL.Query().Get("q"))
+	require.Equal(t, "1", got.URL.Query().Get("quantity"))
+	require.Equal(t, "orders.internal", got.Host)
+	require.Equal(t, "10.0.0.1:0", got.RemoteAddr)
+
+	require.Equal(t, http.StatusTeapot, resp.StatusCode)
+	require.Equal(t, "418 I'm a teapot", resp.StatusDescription)
+	require.Nil(t, resp.Headers)
+	require.Equal(t, []string{"a=1", "b=2"}, resp.MultiValueHeaders["Set-Cookie"])
+}
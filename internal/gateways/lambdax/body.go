@@ -0,0 +1,67 @@
+package lambdax
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+func decodeBody(body string, isBase64 bool) (io.Reader, int64, error) {
+	if !isBase64 {
+		return strings.NewReader(body), int64(len(body)), nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("decodeString: %w", err)
+	}
+
+	return bytes.NewReader(b), int64(len(b)), nil
+}
+
+// encodeBody base64 encodes bodies which aren't text, as the gateway would
+// otherwise mangle them on their way back to the client.
+func encodeBody(header http.Header, body []byte) (string, bool) {
+	if isText(header, body) {
+		return string(body), false
+	}
+
+	return base64.StdEncoding.EncodeToString(body), true
+}
+
+func isText(header http.Header, body []byte) bool {
+	// compressed bodies are binary whatever they contain
+	if enc := header.Get("Content-Encoding"); enc != "" && enc != "identity" {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		return utf8.Valid(body)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return utf8.Valid(body)
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript",
+		"application/x-www-form-urlencoded", "image/svg+xml":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package lambdax
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type (
+	requestContextV1Key          struct{}
+	requestContextV2Key          struct{}
+	requestContextALBKey         struct{}
+	requestContextFunctionURLKey struct{}
+)
+
+func newContextV1(ctx context.Context, rc events.APIGatewayProxyRequestContext) context.Context {
+	return context.WithValue(ctx, requestContextV1Key{}, rc)
+}
+
+func newContextV2(ctx context.Context, rc events.APIGatewayV2HTTPRequestContext) context.Context {
+	return context.WithValue(ctx, requestContextV2Key{}, rc)
+}
+
+func newContextALB(ctx context.Context, rc events.ALBTargetGroupRequestContext) context.Context {
+	return context.WithValue(ctx, requestContextALBKey{}, rc)
+}
+
+func newContextFunctionURL(ctx context.Context, rc events.LambdaFunctionURLRequestContext) context.Context {
+	return context.WithValue(ctx, requestContextFunctionURLKey{}, rc)
+}
+
+// RequestContextV1FromContext returns the API Gateway request context, with
+// the authorizer claims and stage, of a request served by ProxyV1.
+func RequestContextV1FromContext(ctx context.Context) (events.APIGatewayProxyRequestContext, bool) {
+	rc, ok := ctx.Value(requestContextV1Key{}).(events.APIGatewayProxyRequestContext)
+
+	return rc, ok
+}
+
+// RequestContextV2FromContext returns the API Gateway request context, with
+// the authorizer claims and stage, of a request served by ProxyV2.
+func RequestContextV2FromContext(ctx context.Context) (events.APIGatewayV2HTTPRequestContext, bool) {
+	rc, ok := ctx.Value(requestContextV2Key{}).(events.APIGatewayV2HTTPRequestContext)
+
+	return rc, ok
+}
+
+// RequestContextALBFromContext returns the target group of a request served
+// by ProxyALB.
+func RequestContextALBFromContext(ctx context.Context) (events.ALBTargetGroupRequestContext, bool) {
+	rc, ok := ctx.Value(requestContextALBKey{}).(events.ALBTargetGroupRequestContext)
+
+	return rc, ok
+}
+
+// RequestContextFunctionURLFromContext returns the request context of a
+// request served by ProxyFunctionURL.
+func RequestContextFunctionURLFromContext(ctx context.Context) (events.LambdaFunctionURLRequestContext, bool) {
+	rc, ok := ctx.Value(requestContextFunctionURLKey{}).(events.LambdaFunctionURLRequestContext)
+
+	return rc, ok
+}
@@ -0,0 +1,45 @@
+package lambdax
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"net/http"
+)
+
+// ProxyFunctionURL serves a Lambda function URL event, which has the same
+// shape as an HTTP API one.
+func (a *Adapter) ProxyFunctionURL(ctx context.Context, in events.LambdaFunctionURLRequest) (events.LambdaFunctionURLResponse, error) {
+	req, err := newFunctionURLRequest(ctx, in)
+	if err != nil {
+		return events.LambdaFunctionURLResponse{}, fmt.Errorf("newFunctionURLRequest: %w", err)
+	}
+
+	w := newResponseWriter()
+	a.handler.ServeHTTP(w, req)
+
+	resp := newV2Response(w)
+
+	return events.LambdaFunctionURLResponse{
+		StatusCode:      resp.StatusCode,
+		Headers:         resp.Headers,
+		Body:            resp.Body,
+		IsBase64Encoded: resp.IsBase64Encoded,
+		Cookies:         resp.Cookies,
+	}, nil
+}
+
+func newFunctionURLRequest(ctx context.Context, in events.LambdaFunctionURLRequest) (*http.Request, error) {
+	return request{
+		method:   in.RequestContext.HTTP.Method,
+		path:     in.RequestContext.HTTP.Path,
+		rawPath:  in.RawPath,
+		rawQuery: in.RawQueryString,
+		header:   v2Header(in.Headers, in.Cookies),
+		body:     in.Body,
+		isBase64: in.IsBase64Encoded,
+		host:     in.RequestContext.DomainName,
+		proto:    in.RequestContext.HTTP.Protocol,
+		sourceIP: in.RequestContext.HTTP.SourceIP,
+	}.httpRequest(newContextFunctionURL(ctx, in.RequestContext))
+}
@@ -0,0 +1,121 @@
+package lambdax
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// request holds what the event sources have in common.
+type request struct {
+	method string
+	// path is decoded and only used when rawPath, the path as the client sent
+	// it, is missing.
+	path     string
+	rawPath  string
+	rawQuery string
+	header   http.Header
+	body     string
+	isBase64 bool
+	host     string
+	proto    string
+	sourceIP string
+}
+
+func (in request) httpRequest(ctx context.Context) (*http.Request, error) {
+	body, size, err := decodeBody(in.body, in.isBase64)
+	if err != nil {
+		return nil, fmt.Errorf("decodeBody: %w", err)
+	}
+
+	u := &url.URL{Path: in.path, RawQuery: in.rawQuery}
+
+	if in.rawPath != "" {
+		path, err := url.PathUnescape(in.rawPath)
+		if err != nil {
+			return nil, fmt.Errorf("pathUnescape: %w", err)
+		}
+
+		u.Path, u.RawPath = path, in.rawPath
+	}
+
+	req, err := http.NewRequestWithContext(ctx, in.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("newRequestWithContext: %w", err)
+	}
+
+	if in.header != nil {
+		req.Header = in.header
+	}
+
+	req.ContentLength = size
+	req.Host = in.host
+
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+		req.Header.Del("Host")
+	}
+
+	if in.proto != "" {
+		if major, minor, ok := http.ParseHTTPVersion(in.proto); ok {
+			req.Proto, req.ProtoMajor, req.ProtoMinor = in.proto, major, minor
+		}
+	}
+
+	if in.sourceIP != "" {
+		req.RemoteAddr = net.JoinHostPort(in.sourceIP, "0")
+	}
+
+	// as set by the net/http server for incoming requests
+	req.RequestURI = req.URL.RequestURI()
+
+	return req, nil
+}
+
+func singleValueHeader(in map[string]string) http.Header {
+	out := make(http.Header, len(in))
+
+	for key, value := range in {
+		out.Set(key, value)
+	}
+
+	return out
+}
+
+func multiValueHeader(in map[string][]string) http.Header {
+	out := make(http.Header, len(in))
+
+	for key, values := range in {
+		for _, value := range values {
+			out.Add(key, value)
+		}
+	}
+
+	return out
+}
+
+// joinHeader joins multiple header values with commas for the event formats
+// without multi-value headers. Set-Cookie is left out unless keepCookies,
+// as its values can't be joined.
+func joinHeader(header http.Header, keepCookies bool) map[string]string {
+	out := make(map[string]string, len(header))
+
+	for key, values := range header {
+		if len(values) == 0 || (key == "Set-Cookie" && !keepCookies) {
+			continue
+		}
+
+		if key == "Set-Cookie" {
+			out[key] = values[len(values)-1]
+
+			continue
+		}
+
+		out[key] = strings.Join(values, ",")
+	}
+
+	return out
+}
@@ -0,0 +1,44 @@
+package lambdax
+
+import (
+	"bytes"
+	"net/http"
+)
+
+var _ http.ResponseWriter = (*responseWriter)(nil)
+
+// responseWriter buffers the response, since a Lambda returns it whole.
+type responseWriter struct {
+	header      http.Header
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newResponseWriter() *responseWriter {
+	return &responseWriter{
+		header: make(http.Header),
+		code:   http.StatusOK,
+	}
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.code, w.wroteHeader = code, true
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+
+	return w.body.Write(b)
+}
+
+// Flush is a no-op so handlers that flush don't need to check for support.
+func (w *responseWriter) Flush() {}
@@ -0,0 +1,59 @@
+package lambdax
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"net/http"
+	"net/url"
+)
+
+// ProxyV1 serves an API Gateway REST API (payload format 1.0) event.
+func (a *Adapter) ProxyV1(ctx context.Context, in events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	req, err := newV1Request(ctx, in)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, fmt.Errorf("newV1Request: %w", err)
+	}
+
+	w := newResponseWriter()
+	a.handler.ServeHTTP(w, req)
+
+	out := events.APIGatewayProxyResponse{
+		StatusCode:        w.code,
+		MultiValueHeaders: w.header,
+	}
+
+	out.Body, out.IsBase64Encoded = encodeBody(w.header, w.body.Bytes())
+
+	return out, nil
+}
+
+// newV1Request prefers the multi-value headers and query parameters, which
+// the gateway always sends along the single-value ones.
+func newV1Request(ctx context.Context, in events.APIGatewayProxyRequest) (*http.Request, error) {
+	header := multiValueHeader(in.MultiValueHeaders)
+	if len(in.MultiValueHeaders) == 0 {
+		header = singleValueHeader(in.Headers)
+	}
+
+	query := url.Values(in.MultiValueQueryStringParameters)
+	if len(query) == 0 {
+		query = make(url.Values, len(in.QueryStringParameters))
+
+		for key, value := range in.QueryStringParameters {
+			query.Set(key, value)
+		}
+	}
+
+	return request{
+		method:   in.HTTPMethod,
+		path:     in.Path,
+		rawQuery: query.Encode(),
+		header:   header,
+		body:     in.Body,
+		isBase64: in.IsBase64Encoded,
+		host:     in.RequestContext.DomainName,
+		proto:    in.RequestContext.Protocol,
+		sourceIP: in.RequestContext.Identity.SourceIP,
+	}.httpRequest(newContextV1(ctx, in.RequestContext))
+}
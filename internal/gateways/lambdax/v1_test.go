@@ -0,0 +1,58 @@
+package lambdax_test
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/lambdax"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestAdapter_ProxyV1(t *testing.T) {
+	t.Parallel()
+
+	var (
+		got     *http.Request
+		gotBody []byte
+	)
+
+	adapter := lambdax.NewAdapter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+
+		var err error
+
+		gotBody, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+	}))
+
+	resp, err := adapter.ProxyV1(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:            http.MethodPost,
+		Path:                  "/orders",
+		Headers:               map[string]string{"Accept": "application/json"},
+		QueryStringParameters: map[string]string{"dry run": "yes&no"},
+		Body:                  base64.StdEncoding.EncodeToString([]byte(`{"quantity":1}`)),
+		IsBase64Encoded:       true,
+		RequestContext: events.APIGatewayProxyRequestContext{
+			DomainName: "api.example.com",
+			Protocol:   "HTTP/1.1",
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "/orders?dry+run=yes%26no", got.RequestURI)
+	require.Equal(t, "yes&no", got.URL.Query().Get("dry run"))
+	require.Equal(t, "application/json", got.Header.Get("Accept"))
+	require.Equal(t, "api.example.com", got.Host)
+	require.Equal(t, `{"quantity":1}`, string(gotBody))
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, resp.IsBase64Encoded)
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G'}), resp.Body)
+	require.Equal(t, []string{"image/png"}, resp.MultiValueHeaders["Content-Type"])
+}
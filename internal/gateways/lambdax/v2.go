@@ -0,0 +1,63 @@
+package lambdax
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"net/http"
+	"strings"
+)
+
+// ProxyV2 serves an API Gateway HTTP API (payload format 2.0) event.
+func (a *Adapter) ProxyV2(ctx context.Context, in events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	req, err := newV2Request(ctx, in)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{}, fmt.Errorf("newV2Request: %w", err)
+	}
+
+	w := newResponseWriter()
+	a.handler.ServeHTTP(w, req)
+
+	return newV2Response(w), nil
+}
+
+func newV2Request(ctx context.Context, in events.APIGatewayV2HTTPRequest) (*http.Request, error) {
+	return request{
+		method:   in.RequestContext.HTTP.Method,
+		path:     in.RequestContext.HTTP.Path,
+		rawPath:  in.RawPath,
+		rawQuery: in.RawQueryString,
+		header:   v2Header(in.Headers, in.Cookies),
+		body:     in.Body,
+		isBase64: in.IsBase64Encoded,
+		host:     in.RequestContext.DomainName,
+		proto:    in.RequestContext.HTTP.Protocol,
+		sourceIP: in.RequestContext.HTTP.SourceIP,
+	}.httpRequest(newContextV2(ctx, in.RequestContext))
+}
+
+// v2Header puts back the cookies the 2.0 format sends apart; multiple values
+// of other headers arrive joined by commas.
+func v2Header(headers map[string]string, cookies []string) http.Header {
+	out := singleValueHeader(headers)
+
+	if len(cookies) > 0 {
+		out.Set("Cookie", strings.Join(cookies, "; "))
+	}
+
+	return out
+}
+
+// newV2Response returns the cookies set apart, as the 2.0 format has no
+// multi-value headers.
+func newV2Response(w *responseWriter) events.APIGatewayV2HTTPResponse {
+	out := events.APIGatewayV2HTTPResponse{
+		StatusCode: w.code,
+		Headers:    joinHeader(w.header, false),
+		Cookies:    w.header.Values("Set-Cookie"),
+	}
+
+	out.Body, out.IsBase64Encoded = encodeBody(w.header, w.body.Bytes())
+
+	return out
+}
@@ -0,0 +1,278 @@
+package lambdax_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/lambdax"
+	"io"
+	"net/http"
+	"testing"
+)
+
+type ctxKey struct{}
+
+func TestAdapter_ProxyV2_request(t *testing.T) {
+	t.Parallel()
+
+	var (
+		got     *http.Request
+		gotBody []byte
+	)
+
+	adapter := lambdax.NewAdapter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+
+		var err error
+
+		gotBody, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+	}))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "invocation")
+
+	_, err := adapter.ProxyV2(ctx, events.APIGatewayV2HTTPRequest{
+		RawPath:        "/orders/quote",
+		RawQueryString: "quantity=250&format=csv",
+		Cookies:        []string{"session=abc", "theme=dark"},
+		Headers: map[string]string{
+			"host":         "api.example.com",
+			"accept":       "text/csv, application/json",
+			"content-type": "application/octet-stream",
+			"x-api-key":    "key",
+			"x-request-id": "req-1",
+			"traceparent":  "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		},
+		Body:            base64.StdEncoding.EncodeToString([]byte{0xff, 0x00, 0x01}),
+		IsBase64Encoded: true,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			RequestID:  "gw-1",
+			Stage:      "dev",
+			DomainName: "abc.execute-api.us-east-1.amazonaws.com",
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:   http.MethodGet,
+				Path:     "/orders/quote",
+				Protocol: "HTTP/2.0",
+				SourceIP: "203.0.113.5",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, http.MethodGet, got.Method)
+	require.Equal(t, "/orders/quote", got.URL.Path)
+	require.Equal(t, "250", got.URL.Query().Get("quantity"))
+	require.Equal(t, "/orders/quote?quantity=250&format=csv", got.RequestURI)
+	require.Equal(t, "api.example.com", got.Host)
+	require.Empty(t, got.Header.Get("Host"))
+	require.Equal(t, "text/csv, application/json", got.Header.Get("Accept"))
+	require.Equal(t, "key", got.Header.Get("X-Api-Key"))
+	require.Equal(t, "req-1", got.Header.Get("X-Request-Id"))
+	require.Equal(t, "HTTP/2.0", got.Proto)
+	require.Equal(t, 2, got.ProtoMajor)
+	require.Equal(t, "203.0.113.5:0", got.RemoteAddr)
+	require.Equal(t, []byte{0xff, 0x00, 0x01}, gotBody)
+	require.EqualValues(t, 3, got.ContentLength)
+
+	cookie, err := got.Cookie("theme")
+	require.NoError(t, err)
+	require.Equal(t, "dark", cookie.Value)
+
+	require.Equal(t, "invocation", got.Context().Value(ctxKey{}))
+
+	rc, ok := lambdax.RequestContextV2FromContext(got.Context())
+	require.True(t, ok)
+	require.Equal(t, "gw-1", rc.RequestID)
+	require.Equal(t, "dev", rc.Stage)
+}
+
+func TestAdapter_ProxyV2_encodedPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		rawPath        string
+		path           string
+		wantPath       string
+		wantRequestURI string
+	}{
+		{
+			name:           "escaped space",
+			rawPath:        "/orders/a%20b",
+			path:           "/orders/a b",
+			wantPath:       "/orders/a b",
+			wantRequestURI: "/orders/a%20b?quantity=1",
+		},
+		{
+			name:           "escaped slash",
+			rawPath:        "/orders/a%2Fb",
+			path:           "/orders/a/b",
+			wantPath:       "/orders/a/b",
+			wantRequestURI: "/orders/a%2Fb?quantity=1",
+		},
+		{
+			name:           "without raw path",
+			path:           "/orders/a b",
+			wantPath:       "/orders/a b",
+			wantRequestURI: "/orders/a%20b?quantity=1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got *http.Request
+
+			adapter := lambdax.NewAdapter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+			}))
+
+			in := newV2Request(http.MethodGet, tt.rawPath)
+			in.RawQueryString = "quantity=1"
+			in.RequestContext.HTTP.Path = tt.path
+
+			_, err := adapter.ProxyV2(context.Background(), in)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantPath, got.URL.Path)
+			require.Equal(t, tt.wantRequestURI, got.RequestURI)
+		})
+	}
+}
+
+func TestAdapter_ProxyV2_response(t *testing.T) {
+	t.Parallel()
+
+	var gzipped bytes.Buffer
+
+	zw := gzip.NewWriter(&gzipped)
+	_, _ = zw.Write([]byte(`{"data":{}}`))
+	require.NoError(t, zw.Close())
+
+	png := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a}
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		body       []byte
+		wantBody   string
+		wantBase64 bool
+	}{
+		{
+			name:     "json",
+			header:   http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+			body:     []byte(`{"data":{}}`),
+			wantBody: `{"data":{}}`,
+		},
+		{
+			name:     "problem json",
+			header:   http.Header{"Content-Type": {"application/problem+json"}},
+			body:     []byte(`{"status":500}`),
+			wantBody: `{"status":500}`,
+		},
+		{
+			name:     "csv",
+			header:   http.Header{"Content-Type": {"text/csv"}},
+			body:     []byte("pack,quantity\n250,1\n"),
+			wantBody: "pack,quantity\n250,1\n",
+		},
+		{
+			name:       "image",
+			header:     http.Header{"Content-Type": {"image/png"}},
+			body:       png,
+			wantBody:   base64.StdEncoding.EncodeToString(png),
+			wantBase64: true,
+		},
+		{
+			name:       "gzipped json",
+			header:     http.Header{"Content-Type": {"application/json"}, "Content-Encoding": {"gzip"}},
+			body:       gzipped.Bytes(),
+			wantBody:   base64.StdEncoding.EncodeToString(gzipped.Bytes()),
+			wantBase64: true,
+		},
+		{
+			name:       "binary without content type",
+			body:       []byte{0xff, 0xfe},
+			wantBody:   "//4=",
+			wantBase64: true,
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adapter := lambdax.NewAdapter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, values := range tt.header {
+					w.Header()[key] = values
+				}
+
+				_, _ = w.Write(tt.body)
+			}))
+
+			resp, err := adapter.ProxyV2(context.Background(), newV2Request(http.MethodGet, "/"))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, tt.wantBody, resp.Body)
+			require.Equal(t, tt.wantBase64, resp.IsBase64Encoded)
+		})
+	}
+}
+
+func TestAdapter_ProxyV2_responseHeaders(t *testing.T) {
+	t.Parallel()
+
+	adapter := lambdax.NewAdapter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", "Accept-Encoding")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", HttpOnly: true})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark"})
+		w.WriteHeader(http.StatusCreated)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	resp, err := adapter.ProxyV2(context.Background(), newV2Request(http.MethodPost, "/orders"))
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, map[string]string{"Vary": "Accept,Accept-Encoding"}, resp.Headers)
+	require.Equal(t, []string{"session=abc; HttpOnly", "theme=dark"}, resp.Cookies)
+}
+
+func TestAdapter_ProxyV2_invalidRequest(t *testing.T) {
+	t.Parallel()
+
+	adapter := lambdax.NewAdapter(http.NotFoundHandler())
+
+	_, err := adapter.ProxyV2(context.Background(), newV2Request("BAD METHOD", "/"))
+	require.Error(t, err)
+
+	in := newV2Request(http.MethodPost, "/")
+	in.Body, in.IsBase64Encoded = "not base64!", true
+
+	_, err = adapter.ProxyV2(context.Background(), in)
+	require.Error(t, err)
+
+	_, err = adapter.ProxyV2(context.Background(), newV2Request(http.MethodGet, "/orders/%zz"))
+	require.Error(t, err)
+}
+
+func newV2Request(method, path string) events.APIGatewayV2HTTPRequest {
+	return events.APIGatewayV2HTTPRequest{
+		RawPath: path,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: method},
+		},
+	}
+}
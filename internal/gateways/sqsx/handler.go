@@ -0,0 +1,134 @@
+// Package sqsx computes orders for requests queued on SQS.
+package sqsx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type OrderService interface {
+	Create(ctx context.Context, quantity int) (domain.Order, error)
+}
+
+// OrderRequest is the body of a queued message.
+type OrderRequest struct {
+	// RequestID is the producer's reference, passed on to the sink.
+	RequestID string `json:"requestId"`
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
+}
+
+type Handler struct {
+	svc    OrderService
+	sink   Sink
+	logger log.Logger
+}
+
+func NewHandler(svc OrderService, sink Sink, logger log.Logger) *Handler {
+	return &Handler{
+		svc:    svc,
+		sink:   sink,
+		logger: logger,
+	}
+}
+
+// Handle processes every message of the batch and reports the ones that
+// failed for a transient reason, e.g. a service or sink error, which are all
+// SQS makes visible again. Messages that can never succeed, e.g. malformed
+// ones, are logged and dropped. Once a message of a FIFO queue fails the rest
+// of the batch is failed too, to keep the order of its message groups, and
+// once ctx is done the remaining messages are failed without being processed.
+func (h *Handler) Handle(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	var (
+		out    events.SQSEventResponse
+		failed bool
+	)
+
+	for _, msg := range event.Records {
+		if failed || ctx.Err() != nil {
+			out.BatchItemFailures = append(out.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: msg.MessageId})
+
+			continue
+		}
+
+		msgCtx := log.ContextWithAttrs(ctx, slog.String("messageID", msg.MessageId))
+
+		err := h.process(msgCtx, msg)
+
+		switch {
+		case err == nil:
+		case errors.Is(err, domain.ErrInvalidArgument):
+			h.logger.Warn(msgCtx, "message rejected", log.Error(err))
+		default:
+			h.logger.Error(msgCtx, "process message failed", log.Error(err))
+
+			out.BatchItemFailures = append(out.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: msg.MessageId})
+			failed = isFIFO(msg)
+		}
+	}
+
+	return out, nil
+}
+
+func (h *Handler) process(ctx context.Context, msg events.SQSMessage) error {
+	req, err := decodeMessage(msg.Body)
+	if err != nil {
+		return fmt.Errorf("decodeMessage: %w", err)
+	}
+
+	order, err := h.svc.Create(ctx, req.Quantity)
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	result := OrderResult{
+		MessageID: msg.MessageId,
+		RequestID: req.RequestID,
+		Quantity:  req.Quantity,
+		Order:     order,
+	}
+
+	if err := h.sink.Write(ctx, result); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+func decodeMessage(body string) (OrderRequest, error) {
+	var req OrderRequest
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&req); err != nil {
+		return req, fmt.Errorf("%w: decode: %w", domain.ErrInvalidArgument, err)
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return req, fmt.Errorf("%w: message body must contain a single JSON value", domain.ErrInvalidArgument)
+	}
+
+	present, err := validation.JSONPresent([]byte(body))
+	if err != nil {
+		return req, fmt.Errorf("%w: present: %w", domain.ErrInvalidArgument, err)
+	}
+
+	if err := validation.Validate(req, validation.WithPresent(present)); err != nil {
+		return req, fmt.Errorf("%w: %w", domain.ErrInvalidArgument, err)
+	}
+
+	return req, nil
+}
+
+func isFIFO(msg events.SQSMessage) bool {
+	return strings.HasSuffix(msg.EventSourceARN, ".fifo")
+}
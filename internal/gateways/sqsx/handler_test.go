@@ -0,0 +1,177 @@
+package sqsx_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/internal/gateways/sqsx"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	standardQueue = "arn:aws:sqs:us-east-1:123456789012:orders"
+	fifoQueue     = "arn:aws:sqs:us-east-1:123456789012:orders.fifo"
+)
+
+type orderServiceFunc func(ctx context.Context, quantity int) (domain.Order, error)
+
+func (f orderServiceFunc) Create(ctx context.Context, quantity int) (domain.Order, error) {
+	return f(ctx, quantity)
+}
+
+// svc fails for a quantity of 13 and packs everything else in a single pack.
+var svc = orderServiceFunc(func(ctx context.Context, quantity int) (domain.Order, error) {
+	if quantity == 13 {
+		return domain.Order{}, errors.New("unlucky")
+	}
+
+	return domain.Order{Rows: []domain.OrderRow{{Pack: quantity, Quantity: 1}}}, nil
+})
+
+func TestHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		queue       string
+		bodies      []string
+		sinkErr     error
+		wantFailed  []string
+		wantWritten []string
+	}{
+		{
+			name:        "all succeed",
+			queue:       standardQueue,
+			bodies:      []string{`{"quantity": 1}`, `{"quantity": 250, "requestId": "r-2"}`},
+			wantWritten: []string{"m-0", "m-1"},
+		},
+		{
+			name:  "partial failure",
+			queue: standardQueue,
+			bodies: []string{
+				`{"quantity": 1}`,
+				`not json`,
+				`{"quantity": 0}`,
+				`{"quantity": 1, "extra": true}`,
+				`{"quantity": 13}`,
+				`{"quantity": 500}`,
+			},
+			wantFailed:  []string{"m-4"},
+			wantWritten: []string{"m-0", "m-5"},
+		},
+		{
+			name:        "fifo fails the rest of the batch",
+			queue:       fifoQueue,
+			bodies:      []string{`{"quantity": 1}`, `{"quantity": 13}`, `{"quantity": 500}`},
+			wantFailed:  []string{"m-1", "m-2"},
+			wantWritten: []string{"m-0"},
+		},
+		{
+			name:        "fifo drops rejected messages",
+			queue:       fifoQueue,
+			bodies:      []string{`{"quantity": 0}`, `not json`, `{"quantity": 500}`},
+			wantWritten: []string{"m-2"},
+		},
+		{
+			name:       "sink failure",
+			queue:      standardQueue,
+			bodies:     []string{`{"quantity": 1}`},
+			sinkErr:    errors.New("disk full"),
+			wantFailed: []string{"m-0"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var written []string
+
+			sink := sqsx.SinkFunc(func(ctx context.Context, result sqsx.OrderResult) error {
+				if tt.sinkErr != nil {
+					return tt.sinkErr
+				}
+
+				written = append(written, result.MessageID)
+
+				return nil
+			})
+
+			event := events.SQSEvent{}
+
+			for i, body := range tt.bodies {
+				event.Records = append(event.Records, events.SQSMessage{
+					MessageId:      "m-" + strconv.Itoa(i),
+					Body:           body,
+					EventSourceARN: tt.queue,
+				})
+			}
+
+			resp, err := sqsx.NewHandler(svc, sink, log.NewNopLogger()).Handle(context.Background(), event)
+			require.NoError(t, err)
+
+			var failed []string
+			for _, item := range resp.BatchItemFailures {
+				failed = append(failed, item.ItemIdentifier)
+			}
+
+			require.Equal(t, tt.wantFailed, failed)
+			require.Equal(t, tt.wantWritten, written)
+		})
+	}
+}
+
+func TestHandler_Handle_cancelled(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		written     []string
+	)
+
+	defer cancel()
+
+	sink := sqsx.SinkFunc(func(ctx context.Context, result sqsx.OrderResult) error {
+		written = append(written, result.MessageID)
+		cancel()
+
+		return nil
+	})
+
+	resp, err := sqsx.NewHandler(svc, sink, log.NewNopLogger()).Handle(ctx, events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "m-0", Body: `{"quantity": 1}`, EventSourceARN: standardQueue},
+		{MessageId: "m-1", Body: `{"quantity": 1}`, EventSourceARN: standardQueue},
+		{MessageId: "m-2", Body: `{"quantity": 1}`, EventSourceARN: standardQueue},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"m-0"}, written)
+	require.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m-1"}, {ItemIdentifier: "m-2"}}, resp.BatchItemFailures)
+}
+
+func TestJSONSink(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := sqsx.NewHandler(svc, sqsx.NewJSONSink(&buf), log.NewNopLogger())
+
+	resp, err := handler.Handle(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "m-0", Body: `{"quantity": 250, "requestId": "r-1"}`},
+		{MessageId: "m-1", Body: `{"quantity": 500}`},
+	}})
+	require.NoError(t, err)
+	require.Empty(t, resp.BatchItemFailures)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.JSONEq(t, `{"messageId":"m-0","requestId":"r-1","quantity":250,"order":{"rows":[{"pack":250,"quantity":1}]}}`, lines[0])
+	require.JSONEq(t, `{"messageId":"m-1","quantity":500,"order":{"rows":[{"pack":500,"quantity":1}]}}`, lines[1])
+}
@@ -0,0 +1,53 @@
+package sqsx
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/vcraescu/gsh-assessment/internal/domain"
+	"github.com/vcraescu/gsh-assessment/pkg/errs"
+	"io"
+	"sync"
+)
+
+// OrderResult is an order computed for a queued request.
+type OrderResult struct {
+	MessageID string       `json:"messageId"`
+	RequestID string       `json:"requestId,omitempty"`
+	Quantity  int          `json:"quantity"`
+	Order     domain.Order `json:"order"`
+}
+
+// Sink receives the computed orders. A failed write fails the message, so
+// sinks should be idempotent by MessageID: SQS delivers at least once.
+type Sink interface {
+	Write(ctx context.Context, result OrderResult) error
+}
+
+type SinkFunc func(ctx context.Context, result OrderResult) error
+
+func (f SinkFunc) Write(ctx context.Context, result OrderResult) error {
+	return f(ctx, result)
+}
+
+var _ Sink = (*JSONSink)(nil)
+
+// JSONSink writes every result as a line of JSON.
+type JSONSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{enc: json.NewEncoder(w)}
+}
+
+func (s *JSONSink) Write(_ context.Context, result OrderResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.enc.Encode(result); err != nil {
+		return errs.Wrap(err, "encode")
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTimeout  = time.Second
+	DefaultCacheTTL = 2 * time.Second
+)
+
+var ErrTimeout = errors.New("check timed out")
+
+type Severity string
+
+const (
+	// Critical checks take the service down when they fail.
+	Critical Severity = "critical"
+	// NonCritical checks only degrade the report to StatusWarn.
+	NonCritical Severity = "non-critical"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// CheckFunc reports a dependency as unhealthy by returning an error. It should
+// give up once ctx is done.
+type CheckFunc func(ctx context.Context) error
+
+type CheckOption func(c *check)
+
+// WithTimeout bounds how long the check may run before it is failed.
+func WithTimeout(d time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = d
+	}
+}
+
+// WithCacheTTL sets how long a result is reused before the check runs again.
+// Zero runs the check on every probe.
+func WithCacheTTL(d time.Duration) CheckOption {
+	return func(c *check) {
+		c.cacheTTL = d
+	}
+}
+
+func WithSeverity(s Severity) CheckOption {
+	return func(c *check) {
+		c.severity = s
+	}
+}
+
+type Option func(h *Health)
+
+// WithDefaultCacheTTL sets the cache TTL of checks registered without WithCacheTTL.
+func WithDefaultCacheTTL(d time.Duration) Option {
+	return func(h *Health) {
+		h.cacheTTL = d
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(h *Health) {
+		h.now = now
+	}
+}
+
+type Result struct {
+	Status    Status    `json:"status"`
+	Severity  Severity  `json:"severity"`
+	LatencyMS float64   `json:"latencyMs"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Cached    bool      `json:"cached,omitempty"`
+}
+
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	timeout  time.Duration
+	cacheTTL time.Duration
+	severity Severity
+
+	// mu also coalesces concurrent probes into a single run
+	mu     sync.Mutex
+	last   Result
+	expiry time.Time
+}
+
+// Health runs the liveness and readiness checks registered by the components
+// of the service.
+type Health struct {
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu        sync.RWMutex
+	liveness  []*check
+	readiness []*check
+}
+
+func New(opts ...Option) *Health {
+	h := &Health{
+		cacheTTL: DefaultCacheTTL,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// RegisterLiveness adds a check which fails /livez. Only checks whose failure
+// can be fixed by a restart belong here.
+func (h *Health) RegisterLiveness(name string, fn CheckFunc, opts ...CheckOption) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.liveness = append(h.liveness, h.newCheck(name, fn, opts))
+}
+
+// RegisterReadiness adds a check which takes the service out of rotation.
+func (h *Health) RegisterReadiness(name string, fn CheckFunc, opts ...CheckOption) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.readiness = append(h.readiness, h.newCheck(name, fn, opts))
+}
+
+func (h *Health) Live(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := h.liveness
+	h.mu.RUnlock()
+
+	return h.run(ctx, checks)
+}
+
+func (h *Health) Ready(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := h.readiness
+	h.mu.RUnlock()
+
+	return h.run(ctx, checks)
+}
+
+func (h *Health) newCheck(name string, fn CheckFunc, opts []CheckOption) *check {
+	c := &check{
+		name:     name,
+		fn:       fn,
+		timeout:  DefaultTimeout,
+		cacheTTL: h.cacheTTL,
+		severity: Critical,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (h *Health) run(ctx context.Context, checks []*check) Report {
+	report := Report{
+		Status: StatusPass,
+		Checks: make(map[string]Result, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, c := range checks {
+		wg.Add(1)
+
+		go func(c *check) {
+			defer wg.Done()
+
+			res := h.result(ctx, c)
+
+			mu.Lock()
+			report.Checks[c.name] = res
+			mu.Unlock()
+		}(c)
+	}
+
+	wg.Wait()
+
+	for _, res := range report.Checks {
+		switch {
+		case res.Status == StatusFail:
+			report.Status = StatusFail
+		case res.Status == StatusWarn && report.Status == StatusPass:
+			report.Status = StatusWarn
+		}
+	}
+
+	return report
+}
+
+func (h *Health) result(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := h.now(); now.Before(c.expiry) {
+		res := c.last
+		res.Cached = true
+
+		return res
+	}
+
+	// a prober hanging up mustn't fail the check for everyone sharing the cache
+	started := h.now()
+	err := c.run(context.WithoutCancel(ctx))
+	finished := h.now()
+
+	res := Result{
+		Status:    StatusPass,
+		Severity:  c.severity,
+		LatencyMS: float64(finished.Sub(started).Microseconds()) / 1000,
+		CheckedAt: finished,
+	}
+
+	if err != nil {
+		res.Error = err.Error()
+		res.Status = StatusFail
+
+		if c.severity == NonCritical {
+			res.Status = StatusWarn
+		}
+	}
+
+	c.last, c.expiry = res, finished.Add(c.cacheTTL)
+
+	return res
+}
+
+// run doesn't trust fn to honour ctx: a check that hangs is reported as timed
+// out and left to finish in the background.
+func (c *check) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- fmt.Errorf("check panicked: %v", v)
+			}
+		}()
+
+		done <- c.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w after %s", ErrTimeout, c.timeout)
+	}
+}
@@ -0,0 +1,146 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/health"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func pass(context.Context) error { return nil }
+
+func fail(context.Context) error { return errors.New("boom") }
+
+func TestHealth_Ready(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		setup  func(h *health.Health)
+		want   health.Status
+		checks map[string]health.Status
+	}{
+		{
+			name:  "no checks",
+			setup: func(h *health.Health) {},
+			want:  health.StatusPass,
+		},
+		{
+			name: "all pass",
+			setup: func(h *health.Health) {
+				h.RegisterReadiness("a", pass)
+				h.RegisterReadiness("b", pass, health.WithSeverity(health.NonCritical))
+			},
+			want:   health.StatusPass,
+			checks: map[string]health.Status{"a": health.StatusPass, "b": health.StatusPass},
+		},
+		{
+			name: "non-critical failure",
+			setup: func(h *health.Health) {
+				h.RegisterReadiness("a", pass)
+				h.RegisterReadiness("b", fail, health.WithSeverity(health.NonCritical))
+			},
+			want:   health.StatusWarn,
+			checks: map[string]health.Status{"a": health.StatusPass, "b": health.StatusWarn},
+		},
+		{
+			name: "critical failure",
+			setup: func(h *health.Health) {
+				h.RegisterReadiness("a", fail)
+				h.RegisterReadiness("b", fail, health.WithSeverity(health.NonCritical))
+			},
+			want:   health.StatusFail,
+			checks: map[string]health.Status{"a": health.StatusFail, "b": health.StatusWarn},
+		},
+		{
+			name: "panic",
+			setup: func(h *health.Health) {
+				h.RegisterReadiness("a", func(context.Context) error { panic("boom") })
+			},
+			want:   health.StatusFail,
+			checks: map[string]health.Status{"a": health.StatusFail},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := health.New()
+			tt.setup(h)
+
+			got := h.Ready(context.Background())
+
+			require.Equal(t, tt.want, got.Status)
+			require.Len(t, got.Checks, len(tt.checks))
+
+			for name, status := range tt.checks {
+				require.Equal(t, status, got.Checks[name].Status, name)
+			}
+
+			require.Equal(t, health.StatusPass, h.Live(context.Background()).Status)
+		})
+	}
+}
+
+func TestHealth_timeout(t *testing.T) {
+	t.Parallel()
+
+	var (
+		h       = health.New()
+		release = make(chan struct{})
+	)
+
+	defer close(release)
+
+	// ignores ctx on purpose
+	h.RegisterLiveness("stuck", func(context.Context) error {
+		<-release
+
+		return nil
+	}, health.WithTimeout(20*time.Millisecond))
+
+	got := h.Live(context.Background())
+
+	require.Equal(t, health.StatusFail, got.Status)
+	require.Contains(t, got.Checks["stuck"].Error, health.ErrTimeout.Error())
+}
+
+func TestHealth_cache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now   = time.Now()
+		calls atomic.Int32
+		h     = health.New(
+			health.WithDefaultCacheTTL(time.Second),
+			health.WithClock(func() time.Time { return now }),
+		)
+	)
+
+	h.RegisterReadiness("counted", func(context.Context) error {
+		calls.Add(1)
+
+		return nil
+	})
+	h.RegisterReadiness("uncached", pass, health.WithCacheTTL(0))
+
+	got := h.Ready(context.Background())
+	require.False(t, got.Checks["counted"].Cached)
+
+	got = h.Ready(context.Background())
+	require.True(t, got.Checks["counted"].Cached)
+	require.False(t, got.Checks["uncached"].Cached)
+	require.EqualValues(t, 1, calls.Load())
+
+	now = now.Add(time.Second)
+
+	got = h.Ready(context.Background())
+	require.False(t, got.Checks["counted"].Cached)
+	require.EqualValues(t, 2, calls.Load())
+}
@@ -0,0 +1,284 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const combinedTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+type requestInfoKey struct{}
+
+// requestInfo is shared by the access log and the handlers of one request.
+// Middlewares change what they learn about the request on inner contexts the
+// access log never sees, so they fill it in here instead.
+type requestInfo struct {
+	clientAddr string
+	user       string
+}
+
+// SetUser names the authenticated user of the request for the access log.
+func SetUser(ctx context.Context, user string) {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		info.user = user
+	}
+}
+
+// WithTrustedProxies trusts the X-Forwarded-For header of requests coming from
+// prefixes when working out the client address.
+func WithTrustedProxies(prefixes ...netip.Prefix) Option {
+	return func(s *server) {
+		s.trustedProxies = append(s.trustedProxies, prefixes...)
+	}
+}
+
+// WithCombinedAccessLog writes the access log to w in the Apache combined
+// format instead of through the logger.
+func WithCombinedAccessLog(w io.Writer) Option {
+	return func(s *server) {
+		s.combinedLog = w
+	}
+}
+
+// ParseTrustedProxies parses a comma separated list of IP addresses and CIDR
+// prefixes.
+func ParseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var out []netip.Prefix
+
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		if !strings.Contains(field, "/") {
+			addr, err := netip.ParseAddr(field)
+			if err != nil {
+				return nil, fmt.Errorf("parseAddr: %w", err)
+			}
+
+			out = append(out, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("parsePrefix: %w", err)
+		}
+
+		out = append(out, prefix.Masked())
+	}
+
+	return out, nil
+}
+
+type countingReader struct {
+	io.ReadCloser
+
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+
+	return n, err
+}
+
+// withAccessLog logs one record per request once it has been served. The route
+// and trace ID are added by the logger from the request context; the combined
+// format has the trace ID as a trailing field.
+func (s *server) withAccessLog(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			started = time.Now()
+			tw      = &trackingWriter{ResponseWriter: w}
+			body    = &countingReader{ReadCloser: r.Body}
+		)
+
+		info := &requestInfo{clientAddr: s.clientAddr(r)}
+
+		r.Body = body
+		r = r.WithContext(context.WithValue(r.Context(), requestInfoKey{}, info))
+
+		next(tw, r)
+
+		entry := accessLogEntry{
+			time:       started,
+			method:     r.Method,
+			uri:        r.RequestURI,
+			proto:      r.Proto,
+			status:     tw.status(),
+			duration:   time.Since(started),
+			bytesIn:    body.n,
+			bytesOut:   tw.bytes,
+			remoteAddr: info.clientAddr,
+			user:       info.user,
+			userAgent:  r.UserAgent(),
+			referer:    r.Referer(),
+			traceID:    traceID(r.Context()),
+		}
+
+		if s.combinedLog != nil {
+			_, _ = io.WriteString(s.combinedLog, entry.combined())
+
+			return
+		}
+
+		s.logger.Info(r.Context(), "access", entry.attrs()...)
+	}
+}
+
+// ClientAddr is the client address the server worked out for r, honouring
+// WithTrustedProxies. Outside a server it is the peer address.
+func ClientAddr(r *http.Request) string {
+	if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		return info.clientAddr
+	}
+
+	return peerHost(r)
+}
+
+func peerHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// clientAddr is the peer address unless the peer is a trusted proxy, in which
+// case it is the last address in X-Forwarded-For not added by a trusted proxy.
+func (s *server) clientAddr(r *http.Request) string {
+	host := peerHost(r)
+
+	if !s.trusted(host) {
+		return host
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if addr == "" {
+			continue
+		}
+
+		if _, err := netip.ParseAddr(addr); err != nil {
+			// whatever is further left can't be trusted either
+			break
+		}
+
+		host = addr
+
+		if !s.trusted(addr) {
+			break
+		}
+	}
+
+	return host
+}
+
+func (s *server) trusted(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+
+	for _, prefix := range s.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type accessLogEntry struct {
+	time       time.Time
+	method     string
+	uri        string
+	proto      string
+	status     int
+	duration   time.Duration
+	bytesIn    int64
+	bytesOut   int64
+	remoteAddr string
+	user       string
+	userAgent  string
+	referer    string
+	traceID    string
+}
+
+func (e accessLogEntry) attrs() []any {
+	out := []any{
+		slog.String("method", e.method),
+		slog.String("uri", e.uri),
+		slog.Int("status", e.status),
+		slog.Float64("durationMs", float64(e.duration.Microseconds())/1000),
+		slog.Int64("bytesIn", e.bytesIn),
+		slog.Int64("bytesOut", e.bytesOut),
+		slog.String("remoteAddr", e.remoteAddr),
+		slog.String("userAgent", e.userAgent),
+	}
+
+	if e.user != "" {
+		out = append(out, slog.String("user", e.user))
+	}
+
+	return out
+}
+
+// combined formats e as `%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-Agent}i"`
+// followed by the trace ID.
+func (e accessLogEntry) combined() string {
+	size := "-"
+	if e.bytesOut > 0 {
+		size = strconv.FormatInt(e.bytesOut, 10)
+	}
+
+	return fmt.Sprintf("%s - %s [%s] %s %d %s %s %s %s\n",
+		orDash(e.remoteAddr),
+		orDash(strings.ReplaceAll(e.user, " ", "_")),
+		e.time.Format(combinedTimeFormat),
+		quote(e.method+" "+e.uri+" "+e.proto),
+		e.status,
+		size,
+		quote(orDash(e.referer)),
+		quote(orDash(e.userAgent)),
+		orDash(e.traceID),
+	)
+}
+
+func traceID(ctx context.Context) string {
+	if id := trace.SpanContextFromContext(ctx).TraceID(); id.IsValid() {
+		return id.String()
+	}
+
+	return ""
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,233 @@
+package httpserver_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type userKey struct{}
+
+func TestServer_accessLog(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		srv = httpserver.New(log.NewLogger(log.WithWriter(&buf)), httpserver.Use(func(next httpserver.HandlerFunc) httpserver.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) error {
+				httpserver.SetUser(r.Context(), "john")
+
+				return next(w, r.WithContext(context.WithValue(r.Context(), userKey{}, "john")))
+			}
+		}))
+	)
+
+	srv.Post("/orders", func(w http.ResponseWriter, r *http.Request) error {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders?dry=1", strings.NewReader(`{"quantity":1}`))
+	req.RemoteAddr = "203.0.113.5:1234"
+	req.Header.Set("User-Agent", "test-agent")
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, "created", rec.Body.String())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 1)
+	require.Equal(t, 1, strings.Count(lines[0], `"route":`), lines[0])
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	require.NotEmpty(t, got["durationMs"])
+	require.NotEmpty(t, got["requestID"])
+
+	delete(got, "time")
+	delete(got, "durationMs")
+	delete(got, "requestID")
+
+	require.Equal(t, map[string]any{
+		"level":      "INFO",
+		"msg":        "access",
+		"method":     "POST",
+		"route":      "/orders",
+		"uri":        "/orders?dry=1",
+		"status":     float64(201),
+		"bytesIn":    float64(14),
+		"bytesOut":   float64(7),
+		"remoteAddr": "203.0.113.5",
+		"userAgent":  "test-agent",
+		"user":       "john",
+	}, got)
+}
+
+func TestServer_accessLogClientAddr(t *testing.T) {
+	t.Parallel()
+
+	trusted, err := httpserver.ParseTrustedProxies("10.0.0.0/8, 192.0.2.1")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		forwardedFor   []string
+		wantRemoteAddr string
+	}{
+		{
+			name:           "untrusted peer",
+			remoteAddr:     "203.0.113.5:1234",
+			forwardedFor:   []string{"198.51.100.1"},
+			wantRemoteAddr: "203.0.113.5",
+		},
+		{
+			name:           "trusted peer",
+			remoteAddr:     "10.0.0.1:1234",
+			forwardedFor:   []string{"198.51.100.1"},
+			wantRemoteAddr: "198.51.100.1",
+		},
+		{
+			name:           "chain of trusted proxies",
+			remoteAddr:     "10.0.0.1:1234",
+			forwardedFor:   []string{"198.51.100.1, 192.0.2.1", "10.0.0.2"},
+			wantRemoteAddr: "198.51.100.1",
+		},
+		{
+			name:           "spoofed by the client",
+			remoteAddr:     "10.0.0.1:1234",
+			forwardedFor:   []string{"127.0.0.1, 198.51.100.1"},
+			wantRemoteAddr: "198.51.100.1",
+		},
+		{
+			name:           "garbage",
+			remoteAddr:     "10.0.0.1:1234",
+			forwardedFor:   []string{"198.51.100.1, unknown"},
+			wantRemoteAddr: "10.0.0.1",
+		},
+		{
+			name:           "only trusted proxies",
+			remoteAddr:     "10.0.0.1:1234",
+			forwardedFor:   []string{"10.0.0.3, 10.0.0.2"},
+			wantRemoteAddr: "10.0.0.3",
+		},
+		{
+			name:           "no header",
+			remoteAddr:     "[::ffff:10.0.0.1]:1234",
+			wantRemoteAddr: "::ffff:10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				logger = &recordingLogger{}
+				srv    = httpserver.New(logger, httpserver.WithTrustedProxies(trusted...))
+			)
+
+			srv.Get("/", func(w http.ResponseWriter, r *http.Request) error {
+				return nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.RemoteAddr = tt.remoteAddr
+
+			for _, v := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+
+			srv.ServeHTTP(httptest.NewRecorder(), req)
+
+			got, ok := logger.find("access")
+			require.True(t, ok)
+			require.Equal(t, tt.wantRemoteAddr, got.attrs["remoteAddr"])
+		})
+	}
+}
+
+func TestServer_combinedAccessLog(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf    bytes.Buffer
+		logger = &recordingLogger{}
+		srv    = httpserver.New(logger, httpserver.WithCombinedAccessLog(&buf))
+	)
+
+	srv.Get("/orders/quote", func(w http.ResponseWriter, r *http.Request) error {
+		httpserver.SetUser(r.Context(), "john doe")
+
+		_, _ = w.Write([]byte("quote"))
+
+		return nil
+	})
+	srv.Get("/empty", func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/quote?quantity=1", http.NoBody)
+	req.RemoteAddr = "203.0.113.5:1234"
+	req.Header.Set("User-Agent", `agent "1"`)
+	req.Header.Set("Referer", "https://example.com/")
+	req = req.WithContext(trace.ContextWithSpanContext(req.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})))
+
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/empty", http.NoBody))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.Regexp(t, regexp.MustCompile(
+		`^203\.0\.113\.5 - john_doe \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] `+
+			`"GET /orders/quote\?quantity=1 HTTP/1\.1" 200 5 "https://example\.com/" "agent \\"1\\"" `+
+			`4bf92f3577b34da6a3ce929d0e0e4736$`,
+	), lines[0])
+	require.Regexp(t, regexp.MustCompile(`"GET /empty HTTP/1\.1" 204 - "-" "-" -$`), lines[1])
+	require.True(t, strings.HasPrefix(lines[1], "192.0.2.1 - - ["), lines[1])
+
+	_, ok := logger.find("access")
+	require.False(t, ok)
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	got, err := httpserver.ParseTrustedProxies(" 10.1.2.3/8, ::1 ,,192.0.2.1")
+	require.NoError(t, err)
+	require.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("::1/128"),
+		netip.MustParsePrefix("192.0.2.1/32"),
+	}, got)
+
+	_, err = httpserver.ParseTrustedProxies("10.0.0.0/33")
+	require.Error(t, err)
+
+	_, err = httpserver.ParseTrustedProxies("proxy")
+	require.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package httpserver
+
+type group struct {
+	Server
+
+	opts []RouteOption
+}
+
+// Group returns a view of srv which applies opts to every route registered
+// through it, e.g. the middlewares guarding the admin endpoints.
+func Group(srv Server, opts ...RouteOption) Server {
+	return &group{
+		Server: srv,
+		opts:   opts,
+	}
+}
+
+func (g *group) Post(pattern string, h HandlerFunc, opts ...RouteOption) {
+	g.Server.Post(pattern, h, g.with(opts)...)
+}
+
+func (g *group) Get(pattern string, h HandlerFunc, opts ...RouteOption) {
+	g.Server.Get(pattern, h, g.with(opts)...)
+}
+
+func (g *group) with(opts []RouteOption) []RouteOption {
+	out := make([]RouteOption, 0, len(g.opts)+len(opts))
+	out = append(out, g.opts...)
+
+	return append(out, opts...)
+}
@@ -0,0 +1,60 @@
+package httpserver
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type inflightRequest struct {
+	method  string
+	uri     string
+	started time.Time
+}
+
+// inflight keeps track of the requests being served so the ones still running
+// when the shutdown deadline expires can be reported.
+type inflight struct {
+	mu       sync.Mutex
+	next     uint64
+	requests map[uint64]inflightRequest
+}
+
+func newInflight() *inflight {
+	return &inflight{requests: make(map[uint64]inflightRequest)}
+}
+
+func (f *inflight) track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		id := f.next
+		f.next++
+		f.requests[id] = inflightRequest{method: r.Method, uri: r.RequestURI, started: time.Now()}
+		f.mu.Unlock()
+
+		defer func() {
+			f.mu.Lock()
+			delete(f.requests, id)
+			f.mu.Unlock()
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// list returns the requests in flight, oldest first.
+func (f *inflight) list() []inflightRequest {
+	f.mu.Lock()
+	out := make([]inflightRequest, 0, len(f.requests))
+	for _, r := range f.requests {
+		out = append(out, r)
+	}
+	f.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].started.Before(out[j].started)
+	})
+
+	return out
+}
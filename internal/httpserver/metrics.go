@@ -0,0 +1,63 @@
+package httpserver
+
+import (
+	"github.com/vcraescu/gsh-assessment/pkg/metrics"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type httpMetrics struct {
+	requests *metrics.Counter
+	duration *metrics.Histogram
+	inflight *metrics.Gauge
+	panics   *metrics.Counter
+}
+
+// WithMetrics records the count, latency and concurrency of requests per route
+// and status, as well as recovered panics, in reg.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(s *server) {
+		s.metrics = &httpMetrics{
+			requests: reg.Counter("http_requests_total",
+				"Requests served, by method, route and status code.",
+				"method", "route", "code"),
+			duration: reg.Histogram("http_request_duration_seconds",
+				"Time to serve a request, by method, route and status code.",
+				metrics.DefBuckets, "method", "route", "code"),
+			inflight: reg.Gauge("http_requests_in_flight",
+				"Requests being served, by route.",
+				"route"),
+			panics: reg.Counter("http_panics_total",
+				"Panics recovered from handlers."),
+		}
+	}
+}
+
+// withMetrics labels requests by the route pattern rather than the path, so
+// the number of series stays bounded.
+func (s *server) withMetrics(pattern string, handlers map[string]HandlerFunc, next http.HandlerFunc) http.HandlerFunc {
+	if s.metrics == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		method := r.Method
+		if _, ok := handlers[method]; !ok {
+			method = "other"
+		}
+
+		s.metrics.inflight.Inc(pattern)
+		defer s.metrics.inflight.Dec(pattern)
+
+		tw := &trackingWriter{ResponseWriter: w}
+		started := time.Now()
+
+		next(tw, r)
+
+		code := strconv.Itoa(tw.status())
+
+		s.metrics.requests.Inc(method, pattern, code)
+		s.metrics.duration.Observe(time.Since(started).Seconds(), method, pattern, code)
+	}
+}
@@ -0,0 +1,54 @@
+package httpserver_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServer_metrics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		reg = metrics.NewRegistry()
+		srv = httpserver.New(log.NewNopLogger(), httpserver.WithMetrics(reg))
+	)
+
+	srv.Get("/orders/quote", okHandler)
+	srv.Post("/orders", func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return nil
+	})
+	srv.Get("/panic", func(w http.ResponseWriter, r *http.Request) error {
+		panic("boom")
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/orders/quote?quantity=1", http.NoBody),
+		httptest.NewRequest(http.MethodGet, "/orders/quote?quantity=2", http.NoBody),
+		httptest.NewRequest(http.MethodPost, "/orders", http.NoBody),
+		httptest.NewRequest(http.MethodDelete, "/orders", http.NoBody),
+		httptest.NewRequest(http.MethodGet, "/panic", http.NoBody),
+	} {
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, reg.Write(&buf))
+
+	got := buf.String()
+
+	require.Contains(t, got, `http_requests_total{method="GET",route="/orders/quote",code="200"} 2`+"\n")
+	require.Contains(t, got, `http_requests_total{method="POST",route="/orders",code="400"} 1`+"\n")
+	require.Contains(t, got, `http_requests_total{method="other",route="/orders",code="405"} 1`+"\n")
+	require.Contains(t, got, `http_requests_total{method="GET",route="/panic",code="500"} 1`+"\n")
+	require.Contains(t, got, `http_request_duration_seconds_count{method="GET",route="/orders/quote",code="200"} 2`+"\n")
+	require.Contains(t, got, `http_requests_in_flight{route="/orders/quote"} 0`+"\n")
+	require.Contains(t, got, "http_panics_total 1\n")
+}
@@ -0,0 +1,180 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/pkg/openapi"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenAPI builds the document describing every route registered on srv.
+func OpenAPI(srv Server, info openapi.Info) *openapi.Document {
+	doc := openapi.New(info)
+
+	for _, route := range srv.Routes() {
+		item, ok := doc.Paths[route.Pattern]
+		if !ok {
+			item = &openapi.PathItem{}
+			doc.Paths[route.Pattern] = item
+		}
+
+		item.Set(route.Method, operation(doc, route))
+	}
+
+	return doc
+}
+
+func operation(doc *openapi.Document, route Route) *openapi.Operation {
+	op := &openapi.Operation{
+		Summary:     route.Summary,
+		OperationID: operationID(route),
+		Responses:   make(map[string]*openapi.Response),
+	}
+
+	if route.Query != nil {
+		op.Parameters = queryParameters(doc, route.Query)
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  content(doc, *route.Request),
+		}
+	}
+
+	for code, payload := range route.Responses {
+		op.Responses[strconv.Itoa(code)] = response(doc, code, payload)
+	}
+
+	for code, payload := range route.Errors {
+		op.Responses[strconv.Itoa(code)] = response(doc, code, payload)
+	}
+
+	if len(op.Responses) == 0 {
+		op.Responses[strconv.Itoa(http.StatusOK)] = &openapi.Response{Description: http.StatusText(http.StatusOK)}
+	}
+
+	return op
+}
+
+func operationID(route Route) string {
+	var b strings.Builder
+
+	b.WriteString(strings.ToLower(route.Method))
+
+	for _, part := range strings.FieldsFunc(route.Pattern, func(r rune) bool { return r == '/' || r == '.' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
+
+func queryParameters(doc *openapi.Document, t reflect.Type) []openapi.Parameter {
+	schema := doc.Resolve(doc.SchemaFor(t))
+	if schema == nil || schema.Type != "object" {
+		return nil
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	out := make([]openapi.Parameter, 0, len(names))
+
+	for _, name := range names {
+		out = append(out, openapi.Parameter{
+			Name:     name,
+			In:       "query",
+			Required: contains(schema.Required, name),
+			Schema:   schema.Properties[name],
+		})
+	}
+
+	return out
+}
+
+func response(doc *openapi.Document, code int, payload Payload) *openapi.Response {
+	resp := &openapi.Response{Description: http.StatusText(code)}
+
+	if payload.Type != nil {
+		resp.Content = content(doc, payload)
+	}
+
+	return resp
+}
+
+func content(doc *openapi.Document, payload Payload) map[string]*openapi.MediaType {
+	out := make(map[string]*openapi.MediaType, len(payload.MediaTypes))
+
+	for _, mediaType := range payload.MediaTypes {
+		mt := &openapi.MediaType{}
+		if payload.Type != nil {
+			mt.Schema = doc.SchemaFor(payload.Type)
+		}
+
+		out[mediaType] = mt
+	}
+
+	return out
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+const maxSchemaValidationBytes = 1 << 20
+
+// errInvalidArgument prefixes schema errors like the handlers' own validation
+// errors.
+var errInvalidArgument = errors.New("invalid argument")
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// withSchemaValidation checks JSON bodies only. Other media types and bodies
+// which aren't valid JSON are left to the handler's own decoding.
+func withSchemaValidation(doc *openapi.Document, schema *openapi.Schema, next HandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "" && mediaType != mimeJSON {
+			return next(w, r)
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSchemaValidationBytes+1))
+		r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+
+		if err != nil || len(body) > maxSchemaValidationBytes {
+			return next(w, r)
+		}
+
+		var v any
+		if err := json.Unmarshal(body, &v); err != nil {
+			return next(w, r)
+		}
+
+		err = doc.Validate(schema, v)
+		if err == nil {
+			return next(w, r)
+		}
+
+		return WriteError(w, r, http.StatusBadRequest, fmt.Errorf("%w: %w", errInvalidArgument, err))
+	}
+}
@@ -0,0 +1,210 @@
+package httpserver_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/openapi"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type quantityRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+type quantityResponse struct {
+	Total int `json:"total"`
+}
+
+func TestOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	srv := httpserver.New(log.NewNopLogger())
+	srv.Post("/orders", okHandler,
+		httpserver.WithSummary("create"),
+		httpserver.WithRequest(quantityRequest{}),
+		httpserver.WithResponse(http.StatusOK, quantityResponse{}),
+		httpserver.WithError(http.StatusBadRequest, nil, "text/plain"),
+	)
+	srv.Get("/orders/quote", okHandler, httpserver.WithQuery(quantityRequest{}))
+	srv.Get("/healthz", okHandler)
+
+	got, err := json.Marshal(httpserver.OpenAPI(srv, openapi.Info{Title: "test", Version: "1"}))
+	require.NoError(t, err)
+
+	want := `{
+		"openapi": "3.1.0",
+		"info": {"title": "test", "version": "1"},
+		"paths": {
+			"/healthz": {
+				"get": {"operationId": "getHealthz", "responses": {"200": {"description": "OK"}}}
+			},
+			"/orders": {
+				"post": {
+					"summary": "create",
+					"operationId": "postOrders",
+					"requestBody": {
+						"required": true,
+						"content": {
+							"application/json": {"schema": {"$ref": "#/components/schemas/httpserver_test.quantityRequest"}}
+						}
+					},
+					"responses": {
+						"200": {
+							"description": "OK",
+							"content": {
+								"application/json": {"schema": {"$ref": "#/components/schemas/httpserver_test.quantityResponse"}}
+							}
+						},
+						"400": {"description": "Bad Request"}
+					}
+				}
+			},
+			"/orders/quote": {
+				"get": {
+					"operationId": "getOrdersQuote",
+					"parameters": [
+						{"name": "quantity", "in": "query", "required": true, "schema": {"type": "integer", "minimum": 1}}
+					],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"httpserver_test.quantityRequest": {
+					"type": "object",
+					"properties": {"quantity": {"type": "integer", "minimum": 1}},
+					"required": ["quantity"],
+					"additionalProperties": false
+				},
+				"httpserver_test.quantityResponse": {
+					"type": "object",
+					"properties": {"total": {"type": "integer"}},
+					"additionalProperties": false
+				}
+			}
+		}
+	}`
+
+	require.JSONEq(t, want, string(got))
+}
+
+func TestWithSchemaValidation(t *testing.T) {
+	t.Parallel()
+
+	// stands in for auth: schema errors must not leak to rejected callers
+	authenticated := func(next httpserver.HandlerFunc) httpserver.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return nil
+			}
+
+			return next(w, r)
+		}
+	}
+
+	srv := httpserver.New(log.NewNopLogger(), httpserver.WithSchemaValidation(), httpserver.Use(authenticated))
+	srv.Post("/orders", okHandler, httpserver.WithRequest(quantityRequest{}))
+
+	tests := []struct {
+		name          string
+		contentType   string
+		accept        string
+		body          string
+		anonymous     bool
+		wantCode      int
+		wantBody      string
+		wantPlainBody string
+	}{
+		{
+			name:     "valid",
+			body:     `{"quantity": 1}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid",
+			body:     `{"quantity": "250"}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{
+				"error": "invalid argument: quantity: must be an integer; got string",
+				"fields": [{"path": "quantity", "message": "must be an integer; got string"}],
+				"requestId": "req-1"
+			}`,
+		},
+		{
+			name:     "invalid as csv",
+			accept:   "text/csv",
+			body:     `{"quantity": "250"}`,
+			wantCode: http.StatusBadRequest,
+			wantPlainBody: "path,message\n" +
+				"quantity,must be an integer; got string\n",
+		},
+		{
+			name:      "middlewares run first",
+			body:      `{"quantity": "250"}`,
+			anonymous: true,
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:        "other media types are left to the handler",
+			contentType: "application/x-www-form-urlencoded",
+			body:        `quantity=abc`,
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:     "malformed json is left to the handler",
+			body:     `{"quantity"`,
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			req.Header.Set(requestid.Header, "req-1")
+
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			if !tt.anonymous {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantCode, rec.Code)
+
+			if tt.wantBody != "" {
+				require.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+
+			if tt.wantPlainBody != "" {
+				require.Equal(t, tt.wantPlainBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func okHandler(w http.ResponseWriter, _ *http.Request) error {
+	w.WriteHeader(http.StatusOK)
+
+	return nil
+}
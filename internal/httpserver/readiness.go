@@ -0,0 +1,40 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+var ErrDraining = errors.New("server is shutting down")
+
+// Readiness tells load balancers whether new traffic should be routed to the
+// server. Start marks it as draining as soon as shutdown begins.
+type Readiness struct {
+	draining atomic.Bool
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// Ready reports false once Drain was called. A nil Readiness is always ready.
+func (r *Readiness) Ready() bool {
+	return r == nil || !r.draining.Load()
+}
+
+func (r *Readiness) Drain() {
+	if r != nil {
+		r.draining.Store(true)
+	}
+}
+
+// Check fails once the server started draining, so it can be registered as a
+// readiness check.
+func (r *Readiness) Check(context.Context) error {
+	if !r.Ready() {
+		return ErrDraining
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// Problem is an RFC 9457 problem details document.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// trackingWriter remembers whether the response was started, because after
+// that a panic can no longer be turned into a 500, with which status and how
+// much of the body was written.
+type trackingWriter struct {
+	http.ResponseWriter
+
+	wroteHeader bool
+	code        int
+	bytes       int64
+}
+
+func (w *trackingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader, w.code = true, code
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.wroteHeader, w.code = true, http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+
+	return n, err
+}
+
+func (w *trackingWriter) status() int {
+	if !w.wroteHeader {
+		return http.StatusOK
+	}
+
+	return w.code
+}
+
+func (s *server) withRecovery(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			tw = &trackingWriter{ResponseWriter: w}
+			// the headers set before the handler ran, e.g. X-Request-ID
+			header = w.Header().Clone()
+		)
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			// the net/http way to abort a response; let the server handle it
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			s.recovered(tw, r, header, v, debug.Stack())
+		}()
+
+		next(tw, r)
+	}
+}
+
+func (s *server) recovered(w *trackingWriter, r *http.Request, header http.Header, v any, stack []byte) {
+	ctx := r.Context()
+	err := fmt.Errorf("panic: %v", v)
+
+	if s.metrics != nil {
+		s.metrics.panics.Inc()
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	s.logger.Error(ctx, "panic recovered",
+		slog.String("panic", fmt.Sprint(v)),
+		slog.String("method", r.Method),
+		slog.String("uri", r.RequestURI),
+		slog.String("stack", string(stack)),
+	)
+
+	if w.wroteHeader {
+		return
+	}
+
+	// whatever the handler set, e.g. ETag or Content-Length, describes a
+	// response which was never written
+	for key := range w.Header() {
+		delete(w.Header(), key)
+	}
+
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusInternalServerError)
+
+	_ = json.NewEncoder(w).Encode(Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(http.StatusInternalServerError),
+		Status:    http.StatusInternalServerError,
+		Instance:  r.URL.Path,
+		RequestID: requestid.FromContext(ctx),
+	})
+}
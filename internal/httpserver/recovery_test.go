@@ -0,0 +1,151 @@
+package httpserver_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type record struct {
+	msg   string
+	attrs map[string]string
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	records []record
+}
+
+func (l *recordingLogger) Debug(ctx context.Context, msg string, args ...any) {
+	l.add(ctx, msg, args)
+}
+
+func (l *recordingLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.add(ctx, msg, args)
+}
+
+func (l *recordingLogger) Warn(ctx context.Context, msg string, args ...any) {
+	l.add(ctx, msg, args)
+}
+
+func (l *recordingLogger) Error(ctx context.Context, msg string, args ...any) {
+	l.add(ctx, msg, args)
+}
+
+func (l *recordingLogger) With(...any) log.Logger {
+	return l
+}
+
+func (l *recordingLogger) add(ctx context.Context, msg string, args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rec := record{msg: msg, attrs: make(map[string]string)}
+
+	for _, attr := range log.AttrsFromContext(ctx) {
+		rec.attrs[attr.Key] = attr.Value.String()
+	}
+
+	for _, arg := range args {
+		if attr, ok := arg.(slog.Attr); ok {
+			rec.attrs[attr.Key] = attr.Value.String()
+		}
+	}
+
+	l.records = append(l.records, rec)
+}
+
+func (l *recordingLogger) find(msg string) (record, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, rec := range l.records {
+		if rec.msg == msg {
+			return rec, true
+		}
+	}
+
+	return record{}, false
+}
+
+func TestServer_recovery(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger   = &recordingLogger{}
+		recorder = tracetest.NewSpanRecorder()
+		tracer   = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+		srv      = httpserver.New(logger)
+	)
+
+	srv.Post("/orders", func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Header().Set("Content-Length", "42")
+
+		var packs []int
+
+		_ = 250 / len(packs)
+
+		return nil
+	})
+
+	ctx, span := tracer.Start(context.Background(), "request")
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", http.NoBody).WithContext(ctx)
+	req.Header.Set(requestid.Header, "req-1")
+
+	rec := httptest.NewRecorder()
+	require.NotPanics(t, func() { srv.ServeHTTP(rec, req) })
+
+	span.End()
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	require.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	require.Equal(t, "req-1", rec.Header().Get(requestid.Header))
+	require.Empty(t, rec.Header().Get("ETag"))
+	require.Empty(t, rec.Header().Get("Content-Length"))
+	require.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Internal Server Error",
+		"status": 500,
+		"instance": "/orders",
+		"requestId": "req-1"
+	}`, rec.Body.String())
+
+	got, ok := logger.find("panic recovered")
+	require.True(t, ok)
+	require.Equal(t, "runtime error: integer divide by zero", got.attrs["panic"])
+	require.Equal(t, "req-1", got.attrs["requestID"])
+	require.Contains(t, got.attrs["stack"], "recovery_test.go")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Len(t, spans[0].Events(), 1)
+	require.Equal(t, "exception", spans[0].Events()[0].Name)
+}
+
+func TestServer_recoveryAbortHandler(t *testing.T) {
+	t.Parallel()
+
+	srv := httpserver.New(log.NewNopLogger())
+	srv.Get("/abort", func(w http.ResponseWriter, r *http.Request) error {
+		panic(http.ErrAbortHandler)
+	})
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", http.NoBody))
+	})
+}
@@ -0,0 +1,30 @@
+package httpserver
+
+import (
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net/http"
+)
+
+// withRequestID reuses a well-formed X-Request-ID sent by the client or
+// generates one. It is echoed in the response and attached to the context, the
+// log records and the active span.
+func withRequestID(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("http.request_id", id))
+
+		ctx := requestid.NewContext(r.Context(), id)
+		ctx = log.ContextWithAttrs(ctx, slog.String("requestID", id))
+
+		next(w, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,240 @@
+package httpserver
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	mimeXML     = "application/xml"
+	mimeTextXML = "text/xml"
+	mimeCSV     = "text/csv"
+)
+
+var errNotAcceptable = errors.New("not acceptable")
+
+// ErrorResponse is the body of every error the API writes, whichever layer
+// writes it.
+type ErrorResponse struct {
+	XMLName   xml.Name                `json:"-" xml:"response"`
+	Error     string                  `json:"error,omitempty" xml:"error,omitempty"`
+	Fields    []validation.FieldError `json:"fields,omitempty" xml:"fields>field,omitempty"`
+	RequestID string                  `json:"requestId,omitempty" xml:"requestId,omitempty"`
+}
+
+func (r ErrorResponse) MarshalCSV() [][]string {
+	out := [][]string{{"path", "message"}}
+
+	if len(r.Fields) == 0 {
+		return append(out, []string{"", r.Error})
+	}
+
+	for _, f := range r.Fields {
+		out = append(out, []string{f.Path, f.Message})
+	}
+
+	return out
+}
+
+// MarshalXML leaves out the fields element when there are none; omitempty
+// doesn't apply to the parent of a "fields>field" path.
+func (r ErrorResponse) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	type fields struct {
+		Field []validation.FieldError `xml:"field"`
+	}
+
+	out := struct {
+		Error     string  `xml:"error,omitempty"`
+		Fields    *fields `xml:"fields,omitempty"`
+		RequestID string  `xml:"requestId,omitempty"`
+	}{
+		Error:     r.Error,
+		RequestID: r.RequestID,
+	}
+
+	if len(r.Fields) > 0 {
+		out.Fields = &fields{Field: r.Fields}
+	}
+
+	return e.EncodeElement(out, xml.StartElement{Name: xml.Name{Local: "response"}})
+}
+
+// csvMarshaler is implemented by responses which can be rendered as text/csv.
+// The first record is the header.
+type csvMarshaler interface {
+	MarshalCSV() [][]string
+}
+
+type encoder struct {
+	contentType string
+	encode      func(w io.Writer, v any) error
+}
+
+var encoders = map[string]encoder{
+	mimeJSON:    {contentType: mimeJSON, encode: encodeJSON},
+	mimeXML:     {contentType: mimeXML + "; charset=utf-8", encode: encodeXML},
+	mimeTextXML: {contentType: mimeTextXML + "; charset=utf-8", encode: encodeXML},
+	mimeCSV:     {contentType: mimeCSV + "; charset=utf-8", encode: encodeCSV},
+}
+
+// WriteError writes err as an ErrorResponse with the request id and, when err
+// wraps validation.Errors, one entry per field.
+func WriteError(w http.ResponseWriter, r *http.Request, code int, err error) error {
+	resp := ErrorResponse{
+		Error:     err.Error(),
+		RequestID: requestid.FromContext(r.Context()),
+	}
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		resp.Fields = fieldErrs
+	}
+
+	return WriteResponse(w, r, code, resp)
+}
+
+// WriteResponse writes resp in the best format the client accepts. When none
+// of the supported formats is acceptable a 406 is written as JSON instead.
+func WriteResponse(w http.ResponseWriter, r *http.Request, code int, resp any) error {
+	w.Header().Add("Vary", "Accept")
+
+	mediaType, err := negotiate(r.Header.Get("Accept"), offers(resp))
+	if err != nil {
+		mediaType, code = mimeJSON, http.StatusNotAcceptable
+		resp = ErrorResponse{Error: err.Error(), RequestID: requestid.FromContext(r.Context())}
+	}
+
+	enc := encoders[mediaType]
+
+	w.Header().Set("Content-Type", enc.contentType)
+	w.WriteHeader(code)
+
+	if err := enc.encode(w, resp); err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	return nil
+}
+
+func offers(resp any) []string {
+	out := []string{mimeJSON, mimeXML, mimeTextXML}
+
+	if _, ok := resp.(csvMarshaler); ok {
+		out = append(out, mimeCSV)
+	}
+
+	return out
+}
+
+// negotiate picks the offer with the highest quality in the Accept header.
+// Ties are broken by the order of the offers.
+func negotiate(accept string, offers []string) (string, error) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], nil
+	}
+
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+
+	var candidates []candidate
+
+	for _, offer := range offers {
+		if q := acceptQuality(accept, offer); q > 0 {
+			candidates = append(candidates, candidate{mediaType: offer, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w: supported media types are %s", errNotAcceptable, strings.Join(offers, ", "))
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	return candidates[0].mediaType, nil
+}
+
+// acceptQuality returns the q value of the most specific Accept range matching offer.
+func acceptQuality(accept, offer string) float64 {
+	var (
+		q           float64
+		specificity = -1
+	)
+
+	offerType, _, _ := strings.Cut(offer, "/")
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		var s int
+
+		switch {
+		case mediaType == offer:
+			s = 2
+		case mediaType == offerType+"/*":
+			s = 1
+		case mediaType == "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s < specificity {
+			continue
+		}
+
+		specificity, q = s, 1
+
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+	}
+
+	return q
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func encodeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	return xml.NewEncoder(w).Encode(v)
+}
+
+func encodeCSV(w io.Writer, v any) error {
+	m, ok := v.(csvMarshaler)
+	if !ok {
+		return fmt.Errorf("%T cannot be encoded as csv", v)
+	}
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.WriteAll(m.MarshalCSV()); err != nil {
+		return fmt.Errorf("writeAll: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package httpserver
+
+import (
+	"reflect"
+)
+
+const mimeJSON = "application/json"
+
+type Payload struct {
+	Type       reflect.Type
+	MediaTypes []string
+}
+
+// Route describes a registered handler. Besides the method and pattern it
+// carries whatever the route options recorded about the API contract.
+type Route struct {
+	Method      string
+	Pattern     string
+	Summary     string
+	Query       reflect.Type
+	Request     *Payload
+	Responses   map[int]Payload
+	Errors      map[int]Payload
+	Middlewares []Middleware
+}
+
+type RouteOption func(r *Route)
+
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// WithMiddleware wraps the route handler. The first middleware is the outermost.
+func WithMiddleware(mws ...Middleware) RouteOption {
+	return func(r *Route) {
+		r.Middlewares = append(r.Middlewares, mws...)
+	}
+}
+
+func WithSummary(summary string) RouteOption {
+	return func(r *Route) {
+		r.Summary = summary
+	}
+}
+
+// WithQuery documents the query string parameters by the fields of v.
+func WithQuery(v any) RouteOption {
+	return func(r *Route) {
+		r.Query = reflect.TypeOf(v)
+	}
+}
+
+func WithRequest(v any, mediaTypes ...string) RouteOption {
+	return func(r *Route) {
+		r.Request = newPayload(v, mediaTypes)
+	}
+}
+
+func WithResponse(code int, v any, mediaTypes ...string) RouteOption {
+	return func(r *Route) {
+		r.Responses[code] = *newPayload(v, mediaTypes)
+	}
+}
+
+func WithError(code int, v any, mediaTypes ...string) RouteOption {
+	return func(r *Route) {
+		r.Errors[code] = *newPayload(v, mediaTypes)
+	}
+}
+
+func newRoute(method, pattern string, opts []RouteOption) Route {
+	r := Route{
+		Method:    method,
+		Pattern:   pattern,
+		Responses: make(map[int]Payload),
+		Errors:    make(map[int]Payload),
+	}
+
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	return r
+}
+
+func (r Route) wrap(h HandlerFunc) HandlerFunc {
+	return wrap(h, r.Middlewares)
+}
+
+func wrap(h HandlerFunc, mws []Middleware) HandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+
+	return h
+}
+
+func newPayload(v any, mediaTypes []string) *Payload {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{mimeJSON}
+	}
+
+	p := &Payload{MediaTypes: mediaTypes}
+
+	if v != nil {
+		p.Type = reflect.TypeOf(v)
+	}
+
+	return p
+}
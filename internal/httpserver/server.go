@@ -1,12 +1,12 @@
 package httpserver
 
 import (
-	"context"
 	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/openapi"
 	"io"
 	"log/slog"
 	"net/http"
-	"net/http/httptest"
+	"net/netip"
 	"sync"
 )
 
@@ -15,58 +15,128 @@ type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
 type Server interface {
 	http.Handler
 
-	Post(pattern string, h HandlerFunc)
-	Get(pattern string, h HandlerFunc)
+	Post(pattern string, h HandlerFunc, opts ...RouteOption)
+	Get(pattern string, h HandlerFunc, opts ...RouteOption)
+	Routes() []Route
+}
+
+type Option func(s *server)
+
+// WithSchemaValidation rejects JSON request bodies which don't match the
+// schema of the type registered with WithRequest. It runs right before the
+// handler, after every middleware, so unauthenticated or rate limited requests
+// are turned away first.
+func WithSchemaValidation() Option {
+	return func(s *server) {
+		s.validateSchema = true
+	}
+}
+
+// Use wraps every route handler with mws, outside of the route's own middlewares.
+func Use(mws ...Middleware) Option {
+	return func(s *server) {
+		s.middlewares = append(s.middlewares, mws...)
+	}
 }
 
 type server struct {
-	logger   log.Logger
-	mux      *http.ServeMux
-	handlers map[string]map[string]HandlerFunc
-	once     sync.Once
+	logger         log.Logger
+	mux            *http.ServeMux
+	handlers       map[string]map[string]HandlerFunc
+	routes         []Route
+	middlewares    []Middleware
+	metrics        *httpMetrics
+	trustedProxies []netip.Prefix
+	combinedLog    io.Writer
+	validateSchema bool
+	once           sync.Once
 }
 
-func New(logger log.Logger) Server {
-	return &server{
+func New(logger log.Logger, opts ...Option) Server {
+	s := &server{
 		logger:   logger,
 		handlers: make(map[string]map[string]HandlerFunc),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	s.setup(r.Context())
+	s.setup()
 
 	s.mux.ServeHTTP(w, r)
 }
 
-func (s *server) Post(pattern string, h HandlerFunc) {
-	s.registerHandlerFunc(http.MethodPost, pattern, h)
+func (s *server) Post(pattern string, h HandlerFunc, opts ...RouteOption) {
+	s.registerHandlerFunc(newRoute(http.MethodPost, pattern, opts), h)
 }
 
-func (s *server) Get(pattern string, h HandlerFunc) {
-	s.registerHandlerFunc(http.MethodGet, pattern, h)
+func (s *server) Get(pattern string, h HandlerFunc, opts ...RouteOption) {
+	s.registerHandlerFunc(newRoute(http.MethodGet, pattern, opts), h)
 }
 
-func (s *server) setup(ctx context.Context) {
+func (s *server) Routes() []Route {
+	out := make([]Route, len(s.routes))
+	copy(out, s.routes)
+
+	return out
+}
+
+func (s *server) setup() {
 	s.once.Do(func() {
 		s.mux = http.NewServeMux()
 
+		s.setupHandlers()
+
 		for pattern, handlers := range s.handlers {
-			s.mux.HandleFunc(pattern, s.newHandlerFunc(ctx, handlers))
+			s.mux.HandleFunc(pattern, s.newHandlerFunc(pattern, handlers))
 		}
 	})
 }
 
-func (s *server) registerHandlerFunc(method string, pattern string, h HandlerFunc) {
-	if _, ok := s.handlers[pattern]; !ok {
-		s.handlers[pattern] = make(map[string]HandlerFunc)
+func (s *server) registerHandlerFunc(route Route, h HandlerFunc) {
+	if _, ok := s.handlers[route.Pattern]; !ok {
+		s.handlers[route.Pattern] = make(map[string]HandlerFunc)
+	}
+
+	if _, ok := s.handlers[route.Pattern][route.Method]; ok {
+		for i, r := range s.routes {
+			if r.Pattern == route.Pattern && r.Method == route.Method {
+				s.routes = append(s.routes[:i], s.routes[i+1:]...)
+
+				break
+			}
+		}
+	}
+
+	// the middlewares are applied in setupHandlers, once every route is known
+	s.handlers[route.Pattern][route.Method] = h
+	s.routes = append(s.routes, route)
+}
+
+func (s *server) setupHandlers() {
+	var doc *openapi.Document
+	if s.validateSchema {
+		doc = OpenAPI(s, openapi.Info{})
 	}
 
-	s.handlers[pattern][method] = h
+	for _, route := range s.routes {
+		h := s.handlers[route.Pattern][route.Method]
+
+		if doc != nil && route.Request != nil && route.Request.Type != nil {
+			h = withSchemaValidation(doc, doc.SchemaFor(route.Request.Type), h)
+		}
+
+		s.handlers[route.Pattern][route.Method] = wrap(route.wrap(h), s.middlewares)
+	}
 }
 
-func (s *server) newHandlerFunc(ctx context.Context, handlers map[string]HandlerFunc) http.HandlerFunc {
-	return s.withLogger(func(w http.ResponseWriter, r *http.Request) {
+func (s *server) newHandlerFunc(pattern string, handlers map[string]HandlerFunc) http.HandlerFunc {
+	return withRequestID(s.withContextLogger(pattern, s.withMetrics(pattern, handlers, s.withAccessLog(s.withRecovery(func(w http.ResponseWriter, r *http.Request) {
 		defer r.Body.Close()
 
 		h, ok := handlers[r.Method]
@@ -76,42 +146,29 @@ func (s *server) newHandlerFunc(ctx context.Context, handlers map[string]Handler
 			return
 		}
 
-		s.handle(ctx, h)(w, r)
-	})
+		s.handle(h)(w, r)
+	})))))
 }
 
-func (s *server) handle(ctx context.Context, h HandlerFunc) http.HandlerFunc {
+// withContextLogger makes the server logger available to whatever runs with
+// the request context and tags its records with the route pattern.
+func (s *server) withContextLogger(pattern string, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		r = r.WithContext(ctx)
-
-		if err := h(w, r); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-
-			s.logger.Error(ctx, "handler error", log.Error(err))
+		ctx := log.ContextWithAttrs(r.Context(), slog.String("route", pattern))
+		ctx = log.WithContext(ctx, s.logger)
 
-			return
-		}
+		next(w, r.WithContext(ctx))
 	}
 }
 
-func (s *server) withLogger(next http.HandlerFunc) http.HandlerFunc {
+func (s *server) handle(h HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		s.logger.Info(
-			r.Context(), "REQUEST", slog.String("method", r.Method), slog.String("uri", r.RequestURI),
-		)
+		if err := h(w, r); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
 
-		rec := httptest.NewRecorder()
-		next(rec, r)
+			s.logger.Error(r.Context(), "handler error", log.Error(err))
 
-		for key, values := range rec.Header() {
-			for _, value := range values {
-				w.Header().Add(key, value)
-			}
+			return
 		}
-
-		w.WriteHeader(rec.Code)
-		_, _ = io.Copy(w, rec.Body)
-
-		s.logger.Info(r.Context(), "RESPONSE", slog.Int("code", rec.Code), slog.String("uri", r.RequestURI))
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vcraescu/gsh-assessment/internal/httpserver"
 	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -67,6 +68,80 @@ func TestServer(t *testing.T) {
 	})
 }
 
+func TestServer_requestID(t *testing.T) {
+	t.Parallel()
+
+	srv := httpserver.New(log.NewNopLogger())
+	srv.Get("/request-id", func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(requestid.FromContext(r.Context())))
+
+		return err
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/request-id", http.NoBody))
+
+		require.Len(t, rec.Header().Get(requestid.Header), 32)
+		require.Equal(t, rec.Header().Get(requestid.Header), rec.Body.String())
+	})
+
+	t.Run("from client", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/request-id", http.NoBody)
+		req.Header.Set(requestid.Header, "client-id")
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+
+		require.Equal(t, "client-id", rec.Header().Get(requestid.Header))
+		require.Equal(t, "client-id", rec.Body.String())
+	})
+
+	t.Run("invalid from client", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/request-id", http.NoBody)
+		req.Header.Set(requestid.Header, "bad id")
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+
+		require.NotEqual(t, "bad id", rec.Header().Get(requestid.Header))
+		require.Equal(t, rec.Header().Get(requestid.Header), rec.Body.String())
+	})
+}
+
+func TestServer_contextLogger(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logger = &recordingLogger{}
+		srv    = httpserver.New(logger)
+	)
+
+	srv.Get("/orders/quote", func(w http.ResponseWriter, r *http.Request) error {
+		log.FromContext(r.Context()).Info(r.Context(), "from handler")
+		w.WriteHeader(http.StatusOK)
+
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/quote", http.NoBody)
+	req.Header.Set(requestid.Header, "client-id")
+
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec, ok := logger.find("from handler")
+	require.True(t, ok)
+	require.Equal(t, "/orders/quote", rec.attrs["route"])
+	require.Equal(t, "client-id", rec.attrs["requestID"])
+}
+
 func setupTest(t *testing.T, srv httpserver.Server) (address string, client *http.Client, tearDown func()) {
 	t.Helper()
 
@@ -6,33 +6,156 @@ import (
 	"fmt"
 	"github.com/vcraescu/gsh-assessment/pkg/log"
 	"log/slog"
+	"net"
 	"net/http"
+	"time"
 )
 
-func Start(ctx context.Context, logger log.Logger, srv Server, address string) error {
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 15 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 2 * time.Minute
+	DefaultMaxHeaderBytes    = 64 << 10
+	DefaultShutdownTimeout   = 20 * time.Second
+)
+
+// Options configures the http.Server. Zero values fall back to the defaults
+// above; TLS is only enabled when TLS is set.
+type Options struct {
+	Address           string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	TLS               *TLSOptions
+	// Readiness is marked as draining as soon as ctx is done.
+	Readiness *Readiness
+	// ShutdownDelay is how long the server keeps accepting requests after
+	// Readiness started failing, giving load balancers time to notice.
+	ShutdownDelay time.Duration
+	// ShutdownTimeout bounds how long in-flight requests are waited for.
+	ShutdownTimeout time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.ReadHeaderTimeout == 0 {
+		o.ReadHeaderTimeout = DefaultReadHeaderTimeout
+	}
+
+	if o.ReadTimeout == 0 {
+		o.ReadTimeout = DefaultReadTimeout
+	}
+
+	if o.WriteTimeout == 0 {
+		o.WriteTimeout = DefaultWriteTimeout
+	}
+
+	if o.IdleTimeout == 0 {
+		o.IdleTimeout = DefaultIdleTimeout
+	}
+
+	if o.MaxHeaderBytes == 0 {
+		o.MaxHeaderBytes = DefaultMaxHeaderBytes
+	}
+
+	if o.ShutdownTimeout == 0 {
+		o.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	return o
+}
+
+// Start serves srv until ctx is done and then drains it: readiness is flipped,
+// new requests are still accepted for opts.ShutdownDelay and the ones in
+// flight get opts.ShutdownTimeout to complete. Requests still running at the
+// deadline are logged and their connections closed.
+func Start(ctx context.Context, logger log.Logger, srv Server, opts Options) error {
+	opts = opts.withDefaults()
+	requests := newInflight()
+
 	httpSrv := http.Server{
-		Addr: address,
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			r = r.WithContext(ctx)
+		Addr:    opts.Address,
+		Handler: requests.track(srv),
+		// requests must outlive ctx, otherwise they'd be cancelled instead of drained
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithoutCancel(ctx)
+		},
+		ReadHeaderTimeout: opts.ReadHeaderTimeout,
+		ReadTimeout:       opts.ReadTimeout,
+		WriteTimeout:      opts.WriteTimeout,
+		IdleTimeout:       opts.IdleTimeout,
+		MaxHeaderBytes:    opts.MaxHeaderBytes,
+	}
+
+	if opts.TLS != nil {
+		tlsConfig, err := newTLSConfig(ctx, logger, *opts.TLS)
+		if err != nil {
+			return fmt.Errorf("newTLSConfig: %w", err)
+		}
 
-			srv.ServeHTTP(w, r)
-		}),
+		httpSrv.TLSConfig = tlsConfig
 	}
 
+	shutdownErr := make(chan error, 1)
+
 	go func() {
 		<-ctx.Done()
-		if err := httpSrv.Shutdown(ctx); err != nil {
-			logger.Error(ctx, "shutdown failed", log.Error(err))
-		}
+
+		shutdownErr <- shutdown(context.WithoutCancel(ctx), logger, &httpSrv, requests, opts)
 	}()
 
-	logger.Info(ctx, "server started", slog.String("address", address))
+	logger.Info(ctx, "server started", slog.String("address", opts.Address), slog.Bool("tls", opts.TLS != nil))
 
-	if err := httpSrv.ListenAndServe(); err != nil {
-		if !errors.Is(err, http.ErrServerClosed) {
-			return fmt.Errorf("listenAndServe: %w", err)
-		}
+	if err := listenAndServe(&httpSrv); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("listenAndServe: %w", err)
+	}
+
+	// ListenAndServe returns as soon as Shutdown is called; wait for the drain
+	return <-shutdownErr
+}
+
+func shutdown(ctx context.Context, logger log.Logger, srv *http.Server, requests *inflight, opts Options) error {
+	opts.Readiness.Drain()
+
+	logger.Info(ctx, "shutting down server",
+		slog.Duration("delay", opts.ShutdownDelay),
+		slog.Duration("timeout", opts.ShutdownTimeout),
+	)
+
+	time.Sleep(opts.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(ctx, opts.ShutdownTimeout)
+	defer cancel()
+
+	err := srv.Shutdown(ctx)
+	if err == nil {
+		logger.Info(ctx, "server stopped")
+
+		return nil
+	}
+
+	for _, r := range requests.list() {
+		logger.Error(ctx, "request still in flight at shutdown deadline",
+			slog.String("method", r.method),
+			slog.String("uri", r.uri),
+			slog.Duration("elapsed", time.Since(r.started)),
+		)
+	}
+
+	if closeErr := srv.Close(); closeErr != nil {
+		err = errors.Join(err, closeErr)
+	}
+
+	return fmt.Errorf("shutdown: %w", err)
+}
+
+func listenAndServe(srv *http.Server) error {
+	if srv.TLSConfig == nil {
+		return srv.ListenAndServe()
 	}
 
-	return nil
+	// the certificates come from TLSConfig.GetCertificate
+	return srv.ListenAndServeTLS("", "")
 }
@@ -5,6 +5,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vcraescu/gsh-assessment/internal/httpserver"
 	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"net"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -22,7 +24,7 @@ func TestStart(t *testing.T) {
 	go func() {
 		defer close(done)
 
-		err := httpserver.Start(ctx, logger, srv, ":54666")
+		err := httpserver.Start(ctx, logger, srv, httpserver.Options{Address: ":54666"})
 		require.NoError(t, err)
 	}()
 
@@ -34,3 +36,135 @@ func TestStart(t *testing.T) {
 		require.Fail(t, "server didn't shutdown")
 	}
 }
+
+func waitListening(t *testing.T, addr string) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return false
+		}
+
+		_ = conn.Close()
+
+		return true
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStart_drainsInFlightRequests(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr        = freeAddress(t)
+		readiness   = httpserver.NewReadiness()
+		started     = make(chan struct{})
+		release     = make(chan struct{})
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error, 1)
+		srv         = httpserver.New(log.NewNopLogger())
+	)
+
+	srv.Get("/slow", func(w http.ResponseWriter, r *http.Request) error {
+		close(started)
+		<-release
+
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		return nil
+	})
+	srv.Get("/healthz", okHandler)
+
+	go func() {
+		done <- httpserver.Start(ctx, log.NewNopLogger(), srv, httpserver.Options{
+			Address:       addr,
+			Readiness:     readiness,
+			ShutdownDelay: 100 * time.Millisecond,
+		})
+	}()
+
+	waitListening(t, addr)
+
+	respCh := make(chan *http.Response, 1)
+
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		require.NoError(t, err)
+
+		respCh <- resp
+	}()
+
+	<-started
+	cancel()
+
+	require.Eventually(t, func() bool { return !readiness.Ready() }, time.Second, time.Millisecond)
+
+	// new requests are still served during the propagation delay
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://" + addr + "/healthz")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	close(release)
+
+	resp = <-respCh
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, <-done)
+}
+
+func TestStart_reportsRequestsInFlightAtDeadline(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr        = freeAddress(t)
+		logger      = &recordingLogger{}
+		started     = make(chan struct{})
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error, 1)
+		srv         = httpserver.New(log.NewNopLogger())
+	)
+
+	srv.Get("/stuck", func(w http.ResponseWriter, r *http.Request) error {
+		close(started)
+		<-r.Context().Done()
+
+		return nil
+	})
+
+	go func() {
+		done <- httpserver.Start(ctx, logger, srv, httpserver.Options{
+			Address:         addr,
+			ShutdownTimeout: 50 * time.Millisecond,
+		})
+	}()
+
+	waitListening(t, addr)
+
+	go func() {
+		resp, err := http.Get("http://" + addr + "/stuck")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		require.Fail(t, "server didn't shutdown")
+	}
+
+	rec, ok := logger.find("request still in flight at shutdown deadline")
+	require.True(t, ok)
+	require.Equal(t, http.MethodGet, rec.attrs["method"])
+	require.Equal(t, "/stuck", rec.attrs["uri"])
+}
@@ -0,0 +1,277 @@
+package httpserver_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type certAuthority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newCA(t *testing.T) *certAuthority {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &certAuthority{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns a PEM certificate and key signed by the CA.
+func (ca *certAuthority) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	return addr
+}
+
+func startTLS(t *testing.T, opts httpserver.TLSOptions) string {
+	t.Helper()
+
+	srv := httpserver.New(log.NewNopLogger())
+	srv.Get("/healthz", okHandler)
+
+	var (
+		addr        = freeAddress(t)
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error, 1)
+	)
+
+	go func() {
+		done <- httpserver.Start(ctx, log.NewNopLogger(), srv, httpserver.Options{Address: addr, TLS: &opts})
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	waitListening(t, addr)
+
+	return "https://" + addr
+}
+
+func newTLSClient(ca *certAuthority, certs ...tls.Certificate) *http.Client {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: pool, Certificates: certs},
+			DisableKeepAlives: true,
+		},
+	}
+}
+
+func TestStart_TLS(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ca       = newCA(t)
+		dir      = t.TempDir()
+		certFile = filepath.Join(dir, "cert.pem")
+		keyFile  = filepath.Join(dir, "key.pem")
+		modTime  = time.Now().Add(-time.Minute)
+	)
+
+	certPEM, keyPEM := ca.issue(t, 10, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, modTime)
+	writeFile(t, keyFile, keyPEM, modTime)
+
+	addr := startTLS(t, httpserver.TLSOptions{
+		CertFile:       certFile,
+		KeyFile:        keyFile,
+		ReloadInterval: 10 * time.Millisecond,
+	})
+
+	client := newTLSClient(ca)
+
+	servedSerial := func() int64 {
+		resp, err := client.Get(addr + "/healthz")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	require.EqualValues(t, 10, servedSerial())
+
+	certPEM, keyPEM = ca.issue(t, 11, x509.ExtKeyUsageServerAuth)
+	writeFile(t, keyFile, keyPEM, time.Now())
+	writeFile(t, certFile, certPEM, time.Now())
+
+	require.Eventually(t, func() bool {
+		return servedSerial() == 11
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStart_mutualTLS(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ca       = newCA(t)
+		dir      = t.TempDir()
+		certFile = filepath.Join(dir, "cert.pem")
+		keyFile  = filepath.Join(dir, "key.pem")
+		caFile   = filepath.Join(dir, "ca.pem")
+	)
+
+	certPEM, keyPEM := ca.issue(t, 10, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+	writeFile(t, caFile, ca.pem, time.Now())
+
+	addr := startTLS(t, httpserver.TLSOptions{
+		CertFile:          certFile,
+		KeyFile:           keyFile,
+		ClientCAFile:      caFile,
+		RequireClientCert: true,
+	})
+
+	_, err := newTLSClient(ca).Get(addr + "/healthz")
+	require.Error(t, err)
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, 20, x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+
+	resp, err := newTLSClient(ca, clientCert).Get(addr + "/healthz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	otherCA := newCA(t)
+	otherCertPEM, otherKeyPEM := otherCA.issue(t, 30, x509.ExtKeyUsageClientAuth)
+	otherCert, err := tls.X509KeyPair(otherCertPEM, otherKeyPEM)
+	require.NoError(t, err)
+
+	_, err = newTLSClient(ca, otherCert).Get(addr + "/healthz")
+	require.Error(t, err)
+}
+
+func TestStart_timeoutsDefaults(t *testing.T) {
+	t.Parallel()
+
+	srv := httpserver.New(log.NewNopLogger())
+	srv.Get("/healthz", okHandler)
+
+	var (
+		addr        = freeAddress(t)
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error, 1)
+	)
+
+	go func() {
+		done <- httpserver.Start(ctx, log.NewNopLogger(), srv, httpserver.Options{
+			Address:           addr,
+			ReadHeaderTimeout: 50 * time.Millisecond,
+		})
+	}()
+
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	var conn net.Conn
+
+	require.Eventually(t, func() bool {
+		var err error
+		conn, err = net.Dial("tcp", addr)
+
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	defer conn.Close()
+
+	// a client that never finishes its headers is disconnected
+	_, err := conn.Write([]byte("GET /healthz HTTP/1.1\r\nHost: x\r\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	buf := make([]byte, 1)
+	_, err = conn.Read(buf)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, os.ErrDeadlineExceeded)
+}
@@ -22,10 +22,14 @@ func (t *tracedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	otelhttp.NewHandler(t.server, "serverHTTP").ServeHTTP(w, r)
 }
 
-func (t *tracedServer) Post(pattern string, h HandlerFunc) {
-	t.server.Post(pattern, h)
+func (t *tracedServer) Post(pattern string, h HandlerFunc, opts ...RouteOption) {
+	t.server.Post(pattern, h, opts...)
 }
 
-func (t *tracedServer) Get(pattern string, h HandlerFunc) {
-	t.server.Get(pattern, h)
+func (t *tracedServer) Get(pattern string, h HandlerFunc, opts ...RouteOption) {
+	t.server.Get(pattern, h, opts...)
+}
+
+func (t *tracedServer) Routes() []Route {
+	return t.server.Routes()
 }
@@ -0,0 +1,188 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	maxBodyBytes = 1 << 20
+)
+
+var (
+	errKeyTooLong          = fmt.Errorf("%s must be at most %d characters", HeaderKey, maxKeyLength)
+	errFingerprintMismatch = errors.New(HeaderKey + " was already used with a different request")
+	errInProgress          = errors.New("a request with the same " + HeaderKey + " is still being processed")
+)
+
+// Middleware replays the stored response of a request repeated with the same
+// Idempotency-Key by the same caller for ttl. Requests without the header pass
+// through. Server errors are not stored, so that the client can retry them.
+func Middleware(store Store, ttl time.Duration) httpserver.Middleware {
+	return func(next httpserver.HandlerFunc) httpserver.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			key := r.Header.Get(HeaderKey)
+			if key == "" {
+				return next(w, r)
+			}
+
+			if len(key) > maxKeyLength {
+				return httpserver.WriteError(w, r, http.StatusBadRequest, errKeyTooLong)
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					return httpserver.WriteError(w, r, http.StatusRequestEntityTooLarge, err)
+				}
+
+				return fmt.Errorf("readAll: %w", err)
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := fingerprint(r, body)
+
+			key = callerKey(r, key)
+
+			rec, reserved, err := store.Reserve(r.Context(), key, fingerprint, ttl)
+			if err != nil {
+				return fmt.Errorf("reserve: %w", err)
+			}
+
+			if !reserved {
+				return replay(w, r, rec, fingerprint)
+			}
+
+			// a panicking handler would otherwise keep the key reserved for ttl
+			defer func() {
+				if v := recover(); v != nil {
+					_ = store.Release(r.Context(), key)
+
+					panic(v)
+				}
+			}()
+
+			resp := &bufferedWriter{header: make(http.Header)}
+
+			if err := next(resp, r); err != nil {
+				_ = store.Release(r.Context(), key)
+
+				return err
+			}
+
+			if resp.status() >= http.StatusInternalServerError {
+				if err := store.Release(r.Context(), key); err != nil {
+					return fmt.Errorf("release: %w", err)
+				}
+			} else {
+				rec.StatusCode = resp.status()
+				rec.Header = resp.header.Clone()
+				rec.Body = resp.body.Bytes()
+
+				if err := store.Complete(r.Context(), key, rec, ttl); err != nil {
+					return fmt.Errorf("complete: %w", err)
+				}
+			}
+
+			return write(w, resp.status(), resp.header, resp.body.Bytes())
+		}
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, rec Record, fingerprint string) error {
+	switch {
+	case rec.Fingerprint != fingerprint:
+		return httpserver.WriteError(w, r, http.StatusUnprocessableEntity, errFingerprintMismatch)
+	case !rec.Completed:
+		return httpserver.WriteError(w, r, http.StatusConflict, errInProgress)
+	}
+
+	w.Header().Set(HeaderReplayed, "true")
+
+	return write(w, rec.StatusCode, rec.Header, rec.Body)
+}
+
+// callerKey scopes key to the principal's subject, or to the client address of
+// anonymous requests, so that callers picking the same key don't collide.
+func callerKey(r *http.Request, key string) string {
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok && p.Subject != "" {
+		return "sub:" + p.Subject + "\x00" + key
+	}
+
+	return "ip:" + httpserver.ClientAddr(r) + "\x00" + key
+}
+
+// fingerprint covers Accept too, because the stored response was encoded in
+// the media type the first request negotiated.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+
+	for _, s := range []string{r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("Content-Type"), r.Header.Get("Accept")} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func write(w http.ResponseWriter, code int, header http.Header, body []byte) error {
+	for key, values := range header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	w.WriteHeader(code)
+
+	_, err := w.Write(body)
+
+	return err
+}
+
+// bufferedWriter holds the response until it has been stored.
+type bufferedWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) status() int {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+
+	return w.code
+}
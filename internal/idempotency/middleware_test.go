@@ -0,0 +1,249 @@
+package idempotency_test
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"github.com/vcraescu/gsh-assessment/internal/idempotency"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	newHandler := func(calls *atomic.Int32, code int) func(w http.ResponseWriter, r *http.Request) error {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			n := calls.Add(1)
+			body, _ := io.ReadAll(r.Body)
+
+			w.Header().Set("X-Call", strings.Repeat("I", int(n)))
+			w.WriteHeader(code)
+			_, _ = w.Write(body)
+
+			return nil
+		}
+	}
+
+	do := func(t *testing.T, h func(w http.ResponseWriter, r *http.Request) error, key, body string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(idempotency.HeaderKey, key)
+		}
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, h(rec, req))
+
+		return rec
+	}
+
+	t.Run("replays the stored response", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		h := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour)(newHandler(&calls, http.StatusOK))
+
+		first := do(t, h, "key", `{"quantity":1}`)
+		second := do(t, h, "key", `{"quantity":1}`)
+
+		require.EqualValues(t, 1, calls.Load())
+		require.Equal(t, http.StatusOK, second.Code)
+		require.Equal(t, first.Body.String(), second.Body.String())
+		require.Equal(t, "I", second.Header().Get("X-Call"))
+		require.Equal(t, "true", second.Header().Get(idempotency.HeaderReplayed))
+		require.Empty(t, first.Header().Get(idempotency.HeaderReplayed))
+	})
+
+	t.Run("different payload", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		h := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour)(newHandler(&calls, http.StatusOK))
+
+		do(t, h, "key", `{"quantity":1}`)
+		got := do(t, h, "key", `{"quantity":2}`)
+
+		require.EqualValues(t, 1, calls.Load())
+		require.Equal(t, http.StatusUnprocessableEntity, got.Code)
+	})
+
+	t.Run("without key", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		h := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour)(newHandler(&calls, http.StatusOK))
+
+		do(t, h, "", `{"quantity":1}`)
+		do(t, h, "", `{"quantity":1}`)
+
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("server errors are not stored", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		h := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour)(newHandler(&calls, http.StatusInternalServerError))
+
+		do(t, h, "key", `{"quantity":1}`)
+		got := do(t, h, "key", `{"quantity":1}`)
+
+		require.EqualValues(t, 2, calls.Load())
+		require.Equal(t, http.StatusInternalServerError, got.Code)
+	})
+
+	t.Run("in flight", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			started = make(chan struct{})
+			release = make(chan struct{})
+			done    = make(chan struct{})
+			store   = idempotency.NewMemoryStore()
+		)
+
+		slow := idempotency.Middleware(store, time.Hour)(func(w http.ResponseWriter, r *http.Request) error {
+			close(started)
+			<-release
+
+			w.WriteHeader(http.StatusOK)
+
+			return nil
+		})
+
+		go func() {
+			defer close(done)
+
+			do(t, slow, "key", "")
+		}()
+
+		<-started
+
+		got := do(t, slow, "key", "")
+		require.Equal(t, http.StatusConflict, got.Code)
+
+		close(release)
+		<-done
+	})
+
+	t.Run("different accept", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		h := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour)(newHandler(&calls, http.StatusOK))
+
+		do(t, h, "key", `{"quantity":1}`)
+
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"quantity":1}`))
+		req.Header.Set(idempotency.HeaderKey, "key")
+		req.Header.Set("Accept", "application/xml")
+		req = req.WithContext(requestid.NewContext(req.Context(), "req-1"))
+
+		got := httptest.NewRecorder()
+		require.NoError(t, h(got, req))
+
+		require.EqualValues(t, 1, calls.Load())
+		require.Equal(t, http.StatusUnprocessableEntity, got.Code)
+		require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<response><error>Idempotency-Key was already used with a different request</error>`+
+			`<requestId>req-1</requestId></response>`, got.Body.String())
+	})
+
+	t.Run("panic releases the key", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls atomic.Int32
+			store = idempotency.NewMemoryStore()
+		)
+
+		panicky := idempotency.Middleware(store, time.Hour)(func(w http.ResponseWriter, r *http.Request) error {
+			panic("boom")
+		})
+
+		h := idempotency.Middleware(store, time.Hour)(newHandler(&calls, http.StatusOK))
+
+		req := httptest.NewRequest(http.MethodPost, "/orders", http.NoBody)
+		req.Header.Set(idempotency.HeaderKey, "key")
+
+		require.PanicsWithValue(t, "boom", func() { _ = panicky(httptest.NewRecorder(), req) })
+
+		got := do(t, h, "key", "")
+
+		require.EqualValues(t, 1, calls.Load())
+		require.Equal(t, http.StatusOK, got.Code)
+	})
+
+	t.Run("keys are scoped to the caller", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		h := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour)(newHandler(&calls, http.StatusOK))
+
+		doAs := func(subject, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+			req.Header.Set(idempotency.HeaderKey, "1")
+			req = req.WithContext(auth.ContextWithPrincipal(req.Context(), auth.Principal{Subject: subject}))
+
+			rec := httptest.NewRecorder()
+			require.NoError(t, h(rec, req))
+
+			return rec
+		}
+
+		alice := doAs("alice", `{"quantity":1}`)
+		bob := doAs("bob", `{"quantity":2}`)
+		again := doAs("alice", `{"quantity":1}`)
+
+		require.EqualValues(t, 2, calls.Load())
+		require.Equal(t, http.StatusOK, bob.Code)
+		require.Equal(t, `{"quantity":2}`, bob.Body.String())
+		require.Empty(t, bob.Header().Get(idempotency.HeaderReplayed))
+		require.Equal(t, alice.Body.String(), again.Body.String())
+		require.Equal(t, "true", again.Header().Get(idempotency.HeaderReplayed))
+	})
+
+	t.Run("anonymous keys are scoped to the client address", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		h := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour)(newHandler(&calls, http.StatusOK))
+
+		for _, addr := range []string{"203.0.113.5:1234", "203.0.113.6:1234"} {
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"quantity":1}`))
+			req.Header.Set(idempotency.HeaderKey, "1")
+			req.RemoteAddr = addr
+
+			require.NoError(t, h(httptest.NewRecorder(), req))
+		}
+
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		h := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour)(newHandler(&calls, http.StatusOK))
+
+		got := do(t, h, strings.Repeat("k", 256), "")
+
+		require.EqualValues(t, 0, calls.Load())
+		require.Equal(t, http.StatusBadRequest, got.Code)
+	})
+}
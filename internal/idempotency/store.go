@@ -0,0 +1,109 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Record struct {
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+type Store interface {
+	// Reserve atomically creates a pending record for key unless one exists.
+	// It returns the existing record and false when the key is already taken.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, error)
+	// Complete stores the response of a reserved key.
+	Complete(ctx context.Context, key string, rec Record, ttl time.Duration) error
+	// Release drops a reserved key, so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+const sweepInterval = time.Minute
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]Record
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]Record),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if rec, ok := s.records[key]; ok && now.Before(rec.ExpiresAt) {
+		return rec, false, nil
+	}
+
+	rec := Record{
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+	}
+	s.records[key] = rec
+
+	return rec, true, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, rec Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec.Completed = true
+	rec.ExpiresAt = s.now().Add(ttl)
+	s.records[key] = rec
+
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
+
+// sweep drops expired records; it runs at most once per sweepInterval.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+
+	s.lastSweep = now
+
+	for key, rec := range s.records {
+		if !now.Before(rec.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
+
+// Check only succeeds once the store can be locked, so a store stuck behind a
+// long sweep fails the health check by timing out.
+func (s *MemoryStore) Check(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return nil
+}
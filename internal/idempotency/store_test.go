@@ -0,0 +1,50 @@
+package idempotency_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/idempotency"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = context.Background()
+		store = idempotency.NewMemoryStore()
+	)
+
+	_, reserved, err := store.Reserve(ctx, "key", "fp", time.Hour)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	got, reserved, err := store.Reserve(ctx, "key", "other", time.Hour)
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, "fp", got.Fingerprint)
+	require.False(t, got.Completed)
+
+	err = store.Complete(ctx, "key", idempotency.Record{Fingerprint: "fp", StatusCode: 200}, time.Hour)
+	require.NoError(t, err)
+
+	got, _, err = store.Reserve(ctx, "key", "fp", time.Hour)
+	require.NoError(t, err)
+	require.True(t, got.Completed)
+	require.Equal(t, 200, got.StatusCode)
+
+	require.NoError(t, store.Release(ctx, "key"))
+
+	_, reserved, err = store.Reserve(ctx, "key", "fp", time.Hour)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	_, reserved, err = store.Reserve(ctx, "expired", "fp", -time.Second)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	_, reserved, err = store.Reserve(ctx, "expired", "fp", time.Hour)
+	require.NoError(t, err)
+	require.True(t, reserved)
+}
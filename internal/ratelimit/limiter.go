@@ -0,0 +1,193 @@
+package ratelimit
+
+import (
+	"errors"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultIdleTimeout = 10 * time.Minute
+
+var errRateLimitExceeded = errors.New("rate limit exceeded")
+
+// KeyFunc identifies the client a request is accounted to. An empty key falls
+// back to the client IP.
+type KeyFunc func(r *http.Request) string
+
+// ByIP keys on the client address the server resolved, so clients behind a
+// trusted proxy don't share the proxy's bucket.
+func ByIP(r *http.Request) string {
+	return "ip:" + httpserver.ClientAddr(r)
+}
+
+// BySubject keys on the authenticated principal. Anonymous requests get no key
+// and so fall back to the client IP.
+func BySubject(r *http.Request) string {
+	p, ok := auth.PrincipalFromContext(r.Context())
+	if !ok || p.Subject == "" {
+		return ""
+	}
+
+	return "sub:" + p.Subject
+}
+
+type Option func(l *Limiter)
+
+func WithKeyFunc(fn KeyFunc) Option {
+	return func(l *Limiter) {
+		l.keyFunc = fn
+	}
+}
+
+// WithIdleTimeout sets how long a bucket is kept after its last request.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(l *Limiter) {
+		l.idleTimeout = d
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(l *Limiter) {
+		l.now = now
+	}
+}
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+// Limiter is a token bucket per client: each bucket holds up to burst tokens
+// and refills at rate tokens per second.
+type Limiter struct {
+	rate        float64
+	burst       int
+	keyFunc     KeyFunc
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func New(rate float64, burst int, opts ...Option) *Limiter {
+	l := &Limiter{
+		rate:        rate,
+		burst:       burst,
+		keyFunc:     BySubject,
+		idleTimeout: defaultIdleTimeout,
+		now:         time.Now,
+		buckets:     make(map[string]*bucket),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.lastSeen).Seconds()*l.rate)
+	b.lastSeen = now
+
+	out := Result{Limit: l.burst}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		out.Allowed = true
+	} else {
+		out.RetryAfter = l.duration(1 - b.tokens)
+	}
+
+	out.Remaining = int(b.tokens)
+	out.Reset = l.duration(float64(l.burst) - b.tokens)
+
+	return out
+}
+
+func (l *Limiter) Middleware() httpserver.Middleware {
+	return func(next httpserver.HandlerFunc) httpserver.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			key := l.keyFunc(r)
+			if key == "" {
+				key = ByIP(r)
+			}
+
+			res := l.Allow(key)
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+
+			if res.Allowed {
+				return next(w, r)
+			}
+
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+
+			return httpserver.WriteError(w, r, http.StatusTooManyRequests, errRateLimitExceeded)
+		}
+	}
+}
+
+func (l *Limiter) duration(tokens float64) time.Duration {
+	if l.rate <= 0 {
+		return l.idleTimeout
+	}
+
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets idle for longer than idleTimeout; it runs at most once
+// per idleTimeout.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.idleTimeout {
+		return
+	}
+
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= l.idleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.buckets)
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,127 @@
+package ratelimit_test
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/internal/auth"
+	"github.com/vcraescu/gsh-assessment/internal/httpserver"
+	"github.com/vcraescu/gsh-assessment/internal/ratelimit"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	c := &clock{now: time.Unix(0, 0)}
+	l := ratelimit.New(1, 2, ratelimit.WithClock(c.Now))
+
+	require.Equal(t, ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, l.Allow("a"))
+	require.Equal(t, ratelimit.Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second}, l.Allow("a"))
+	require.Equal(t, ratelimit.Result{
+		Limit: 2, Remaining: 0, RetryAfter: time.Second, Reset: 2 * time.Second,
+	}, l.Allow("a"))
+
+	// other clients have their own bucket
+	require.True(t, l.Allow("b").Allowed)
+
+	c.now = c.now.Add(500 * time.Millisecond)
+	require.Equal(t, ratelimit.Result{
+		Limit: 2, Remaining: 0, RetryAfter: 500 * time.Millisecond, Reset: 1500 * time.Millisecond,
+	}, l.Allow("a"))
+
+	c.now = c.now.Add(500 * time.Millisecond)
+	require.True(t, l.Allow("a").Allowed)
+}
+
+func TestLimiter_evictsIdleBuckets(t *testing.T) {
+	t.Parallel()
+
+	c := &clock{now: time.Unix(0, 0)}
+	l := ratelimit.New(1, 1, ratelimit.WithClock(c.Now), ratelimit.WithIdleTimeout(time.Minute))
+
+	l.Allow("a")
+	l.Allow("b")
+	require.Equal(t, 2, l.Len())
+
+	c.now = c.now.Add(time.Minute)
+	l.Allow("c")
+	require.Equal(t, 1, l.Len())
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	t.Parallel()
+
+	l := ratelimit.New(0.5, 1)
+	h := l.Middleware()(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusOK)
+
+		return nil
+	})
+
+	do := func(subject, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", http.NoBody)
+		req.RemoteAddr = remoteAddr
+		// a client-controlled header must not pick the bucket
+		req.Header.Set("X-Forwarded-For", remoteAddr+"0")
+
+		if subject != "" {
+			req = req.WithContext(auth.ContextWithPrincipal(req.Context(), auth.Principal{Subject: subject}))
+		}
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, h(rec, req.WithContext(requestid.NewContext(req.Context(), "req-1"))))
+
+		return rec
+	}
+
+	got := do("alice", "10.0.0.1:1234")
+	require.Equal(t, http.StatusOK, got.Code)
+	require.Equal(t, "1", got.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "0", got.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "2", got.Header().Get("RateLimit-Reset"))
+
+	got = do("alice", "10.0.0.2:1234")
+	require.Equal(t, http.StatusTooManyRequests, got.Code)
+	require.Equal(t, "2", got.Header().Get("Retry-After"))
+	require.JSONEq(t, `{"error": "rate limit exceeded", "requestId": "req-1"}`, got.Body.String())
+
+	// anonymous requests fall back to the client IP
+	require.Equal(t, http.StatusOK, do("", "10.0.0.1:1234").Code)
+	require.Equal(t, http.StatusTooManyRequests, do("", "10.0.0.1:4321").Code)
+}
+
+func TestByIP(t *testing.T) {
+	t.Parallel()
+
+	trusted, err := httpserver.ParseTrustedProxies("10.0.0.0/8")
+	require.NoError(t, err)
+
+	var got string
+
+	srv := httpserver.New(log.NewNopLogger(), httpserver.WithTrustedProxies(trusted...))
+	srv.Get("/", func(w http.ResponseWriter, r *http.Request) error {
+		got = ratelimit.ByIP(r)
+
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Equal(t, "ip:198.51.100.1", got)
+}
@@ -0,0 +1,177 @@
+// Package errs wraps errors with the stack trace of the point they were
+// created or first wrapped at, so logs can tell where an error came from.
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const maxStackDepth = 32
+
+// Coder is implemented by errors carrying a stable, machine readable code.
+type Coder interface {
+	Code() string
+}
+
+// Frame is a function call in a stack trace.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f Frame) String() string {
+	return f.Function + " (" + f.File + ":" + strconv.Itoa(f.Line) + ")"
+}
+
+type withStack struct {
+	msg   string
+	err   error
+	stack []uintptr
+}
+
+// New returns an error with text msg and the stack trace of the caller.
+func New(msg string) error {
+	return &withStack{msg: msg, stack: callers()}
+}
+
+// Wrap returns err prefixed with msg like fmt.Errorf("msg: %w", err). The
+// stack trace of the caller is recorded unless err already carries one. Wrap
+// returns nil when err is nil.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := &withStack{msg: msg, err: err}
+
+	if !hasStack(err) {
+		e.stack = callers()
+	}
+
+	return e
+}
+
+// Wrapf is Wrap with a formatted message.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	e := &withStack{msg: fmt.Sprintf(format, args...), err: err}
+
+	if !hasStack(err) {
+		e.stack = callers()
+	}
+
+	return e
+}
+
+func (e *withStack) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *withStack) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns the stack recorded by e itself, if any.
+func (e *withStack) StackTrace() []Frame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+
+	var (
+		out    []Frame
+		frames = runtime.CallersFrames(e.stack)
+	)
+
+	for {
+		frame, more := frames.Next()
+
+		// the runtime frames are noise
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			out = append(out, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+
+		if !more {
+			return out
+		}
+	}
+}
+
+// Stack returns the innermost stack trace recorded in the chain of err,
+// which is the closest to where the error happened. Of the branches of an
+// errors.Join, the first one carrying a stack trace wins.
+func Stack(err error) []Frame {
+	if e := innermost(err); e != nil {
+		return e.StackTrace()
+	}
+
+	return nil
+}
+
+func innermost(err error) *withStack {
+	var out *withStack
+
+	for err != nil {
+		if e, ok := err.(*withStack); ok && len(e.stack) > 0 {
+			out = e
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, branch := range joined.Unwrap() {
+				if e := innermost(branch); e != nil {
+					return e
+				}
+			}
+
+			return out
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return out
+}
+
+// hasStack reports whether Stack would find a stack trace, without resolving
+// its frames.
+func hasStack(err error) bool {
+	for err != nil {
+		if e, ok := err.(*withStack); ok && len(e.stack) > 0 {
+			return true
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, branch := range joined.Unwrap() {
+				if hasStack(branch) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return false
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+
+	// skip runtime.Callers, callers and the exported function calling it
+	n := runtime.Callers(3, pcs)
+
+	return pcs[:n]
+}
@@ -0,0 +1,57 @@
+package errs_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/errs"
+	"strings"
+	"testing"
+)
+
+var errNotFound = errors.New("not found")
+
+func findAll() error {
+	return errs.Wrap(errNotFound, "findAll")
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	err := errs.Wrapf(findAll(), "create %d", 250)
+
+	require.EqualError(t, err, "create 250: findAll: not found")
+	require.ErrorIs(t, err, errNotFound)
+
+	stack := errs.Stack(err)
+	require.NotEmpty(t, stack)
+	require.True(t, strings.HasSuffix(stack[0].Function, "errs_test.findAll"), stack[0].Function)
+	require.True(t, strings.HasSuffix(stack[0].File, "errs_test.go"), stack[0].File)
+	require.Positive(t, stack[0].Line)
+}
+
+func TestWrap_nil(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, errs.Wrap(nil, "findAll"))
+	require.NoError(t, errs.Wrapf(nil, "create %d", 250))
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	err := errs.New("boom")
+
+	require.EqualError(t, err, "boom")
+	require.True(t, strings.HasSuffix(errs.Stack(err)[0].Function, "errs_test.TestNew"))
+	require.Nil(t, errs.Stack(errNotFound))
+}
+
+func TestStack_join(t *testing.T) {
+	t.Parallel()
+
+	err := errs.Wrap(errors.Join(errNotFound, findAll()), "create")
+
+	stack := errs.Stack(err)
+	require.NotEmpty(t, stack)
+	require.True(t, strings.HasSuffix(stack[0].Function, "errs_test.findAll"), stack[0].Function)
+}
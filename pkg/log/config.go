@@ -0,0 +1,114 @@
+package log
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvLevel              = "LOG_LEVEL"
+	EnvFormat             = "LOG_FORMAT"
+	EnvSamplingFirst      = "LOG_SAMPLING_FIRST"
+	EnvSamplingThereafter = "LOG_SAMPLING_THEREAFTER"
+	EnvSamplingInterval   = "LOG_SAMPLING_INTERVAL"
+	EnvSamplingAlways     = "LOG_SAMPLING_ALWAYS"
+)
+
+// ParseLevel accepts debug, info, warn and error in any case.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return level, fmt.Errorf("unknown log level %q", s)
+	}
+
+	return level, nil
+}
+
+// FromEnv returns the options configured by LOG_LEVEL (debug, info, warn or
+// error) and LOG_FORMAT (json or text). The level is stored in level so it
+// can be changed at runtime. Sampling is enabled by LOG_SAMPLING_FIRST, see
+// samplingFromEnv.
+func FromEnv(level *slog.LevelVar) ([]Option, error) {
+	opts := []Option{WithLevel(level)}
+
+	if v := os.Getenv(EnvLevel); v != "" {
+		l, err := ParseLevel(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvLevel, err)
+		}
+
+		level.Set(l)
+	}
+
+	switch format := Format(strings.ToLower(os.Getenv(EnvFormat))); format {
+	case "":
+	case FormatJSON, FormatText:
+		opts = append(opts, WithFormat(format))
+	default:
+		return nil, fmt.Errorf("%s: unknown log format %q", EnvFormat, format)
+	}
+
+	sampling, err := samplingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	if sampling != nil {
+		opts = append(opts, WithSampling(*sampling))
+	}
+
+	return opts, nil
+}
+
+// samplingFromEnv reads LOG_SAMPLING_FIRST and LOG_SAMPLING_THEREAFTER as
+// integers, LOG_SAMPLING_INTERVAL as a time.ParseDuration value and
+// LOG_SAMPLING_ALWAYS as a level. LOG_SAMPLING_THEREAFTER=0 drops every record
+// after the first ones; unset variables keep the SamplingOptions defaults. It
+// returns nil when sampling isn't enabled.
+func samplingFromEnv() (*SamplingOptions, error) {
+	first := os.Getenv(EnvSamplingFirst)
+	if first == "" {
+		return nil, nil
+	}
+
+	var (
+		opts SamplingOptions
+		err  error
+	)
+
+	if opts.First, err = strconv.Atoi(first); err != nil || opts.First < 1 {
+		return nil, fmt.Errorf("%s: must be a positive integer, got %q", EnvSamplingFirst, first)
+	}
+
+	if v := os.Getenv(EnvSamplingThereafter); v != "" {
+		if opts.Thereafter, err = strconv.Atoi(v); err != nil || opts.Thereafter < 0 {
+			return nil, fmt.Errorf("%s: must be a non-negative integer, got %q", EnvSamplingThereafter, v)
+		}
+
+		if opts.Thereafter == 0 {
+			opts.Thereafter = -1
+		}
+	}
+
+	if v := os.Getenv(EnvSamplingInterval); v != "" {
+		if opts.Interval, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvSamplingInterval, err)
+		}
+	}
+
+	if v := os.Getenv(EnvSamplingAlways); v != "" {
+		level, err := ParseLevel(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvSamplingAlways, err)
+		}
+
+		opts.Always = level
+	}
+
+	return &opts, nil
+}
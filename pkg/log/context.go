@@ -0,0 +1,83 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+type attrsKey struct{}
+
+// ContextWithAttrs returns a context carrying attrs in addition to the ones
+// already attached. Loggers add them to every record logged with the context.
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	prev := AttrsFromContext(ctx)
+
+	out := make([]slog.Attr, 0, len(prev)+len(attrs))
+	out = append(out, prev...)
+	out = append(out, attrs...)
+
+	return context.WithValue(ctx, attrsKey{}, out)
+}
+
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+
+	return attrs
+}
+
+type loggerKey struct{}
+
+// WithContext returns a context carrying logger, for code which has no logger
+// of its own to log through the one set up by the middlewares.
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stored by WithContext or one writing to the
+// slog default logger. Either way the attrs attached with ContextWithAttrs are
+// logged when the logger is called with ctx.
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return logger
+	}
+
+	// a Handler adds the context attrs and trace IDs itself
+	if _, ok := slog.Default().Handler().(*Handler); ok {
+		return defaultLogger{logger: slog.Default()}
+	}
+
+	return &SlogLogger{
+		logger:    slog.Default(),
+		redactor:  newRedactor(DefaultRedactKeys),
+		spanLevel: slog.LevelError,
+	}
+}
+
+// defaultLogger passes records to the slog default logger untouched.
+type defaultLogger struct {
+	logger *slog.Logger
+}
+
+func (l defaultLogger) With(args ...any) Logger {
+	return defaultLogger{logger: l.logger.With(args...)}
+}
+
+func (l defaultLogger) Enabled(ctx context.Context, level slog.Level) bool {
+	return l.logger.Enabled(ctx, level)
+}
+
+func (l defaultLogger) Debug(ctx context.Context, msg string, args ...any) {
+	l.logger.DebugContext(ctx, msg, args...)
+}
+
+func (l defaultLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.logger.InfoContext(ctx, msg, args...)
+}
+
+func (l defaultLogger) Warn(ctx context.Context, msg string, args ...any) {
+	l.logger.WarnContext(ctx, msg, args...)
+}
+
+func (l defaultLogger) Error(ctx context.Context, msg string, args ...any) {
+	l.logger.ErrorContext(ctx, msg, args...)
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/pkg/errs"
+	"log/slog"
+)
+
+const maxErrorDepth = 16
+
+// Error returns an "error" attr describing err and its causes:
+//
+//	{"msg": "findAll: boom", "stack": ["..."], "cause": {"msg": "boom", "type": "*errors.errorString"}}
+//
+// Errors joined with errors.Join are listed under "causes". The attr is empty,
+// and so left out, when err is nil.
+func Error(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	return slog.Any("error", errorValue{err: err})
+}
+
+type errorValue struct {
+	err error
+}
+
+// LogValue defers describing the chain until the record is written.
+func (v errorValue) LogValue() slog.Value {
+	return slog.AnyValue(describeError(v.err, 0))
+}
+
+type errorNode struct {
+	Msg    string      `json:"msg"`
+	Type   string      `json:"type,omitempty"`
+	Code   string      `json:"code,omitempty"`
+	Stack  []string    `json:"stack,omitempty"`
+	Cause  *errorNode  `json:"cause,omitempty"`
+	Causes []errorNode `json:"causes,omitempty"`
+}
+
+func describeError(err error, depth int) errorNode {
+	node := errorNode{Msg: err.Error()}
+
+	if coder, ok := err.(errs.Coder); ok {
+		node.Code = coder.Code()
+	}
+
+	if st, ok := err.(interface{ StackTrace() []errs.Frame }); ok {
+		for _, frame := range st.StackTrace() {
+			node.Stack = append(node.Stack, frame.String())
+		}
+	}
+
+	if depth >= maxErrorDepth {
+		return node
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			c := describeError(cause, depth+1)
+			node.Cause = &c
+		}
+	case interface{ Unwrap() []error }:
+		for _, cause := range u.Unwrap() {
+			if cause != nil {
+				node.Causes = append(node.Causes, describeError(cause, depth+1))
+			}
+		}
+	default:
+		// the root cause; its type tells apart errors with similar messages
+		node.Type = fmt.Sprintf("%T", err)
+	}
+
+	return node
+}
@@ -0,0 +1,76 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/errs"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"strings"
+	"testing"
+)
+
+type codedError struct{}
+
+func (codedError) Error() string { return "out of stock" }
+
+func (codedError) Code() string { return "out_of_stock" }
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		root   = errors.New("connection refused")
+		joined = errors.Join(errs.Wrap(root, "findAll"), codedError{})
+		err    = fmt.Errorf("create: %w", joined)
+		buf    bytes.Buffer
+	)
+
+	log.NewLogger(log.WithWriter(&buf)).Error(context.Background(), "failed", log.Error(err))
+
+	rec := struct {
+		Error map[string]any `json:"error"`
+	}{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+
+	// the stack is checked apart, as it depends on the file layout
+	findAll := rec.Error["cause"].(map[string]any)["causes"].([]any)[0].(map[string]any)
+	stack := findAll["stack"].([]any)
+	require.NotEmpty(t, stack)
+	require.Contains(t, stack[0], "log_test.TestError")
+	delete(findAll, "stack")
+
+	want := `{
+		"msg": "create: findAll: connection refused\nout of stock",
+		"cause": {
+			"msg": "findAll: connection refused\nout of stock",
+			"causes": [
+				{
+					"msg": "findAll: connection refused",
+					"cause": {"msg": "connection refused", "type": "*errors.errorString"}
+				},
+				{"msg": "out of stock", "code": "out_of_stock", "type": "log_test.codedError"}
+			]
+		}
+	}`
+
+	got, err := json.Marshal(rec.Error)
+	require.NoError(t, err)
+	require.JSONEq(t, want, string(got))
+}
+
+func TestError_nil(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	require.NotPanics(t, func() {
+		log.NewLogger(log.WithWriter(&buf)).Error(context.Background(), "failed", log.Error(nil))
+	})
+
+	require.NotContains(t, buf.String(), `"error"`)
+	require.True(t, strings.HasPrefix(buf.String(), "{"))
+}
@@ -0,0 +1,123 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+var _ slog.Handler = (*Handler)(nil)
+
+// Handler is a slog.Handler writing through a Logger, so libraries logging
+// with *slog.Logger get the same output, context attrs and trace IDs:
+//
+//	slog.SetDefault(slog.New(log.NewHandler(logger)))
+type Handler struct {
+	logger Logger
+	// groups[0] holds the attrs added outside of any group
+	groups []handlerGroup
+}
+
+type handlerGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+func NewHandler(logger Logger) *Handler {
+	return &Handler{
+		logger: logger,
+		groups: []handlerGroup{{}},
+	}
+}
+
+type enabler interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if e, ok := h.logger.(enabler); ok {
+		return e.Enabled(ctx, level)
+	}
+
+	return true
+}
+
+func (h *Handler) Handle(ctx context.Context, rec slog.Record) error {
+	attrs := make([]slog.Attr, 0, rec.NumAttrs())
+
+	rec.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+
+		return true
+	})
+
+	args := h.nest(attrs)
+
+	switch {
+	case rec.Level >= slog.LevelError:
+		h.logger.Error(ctx, rec.Message, args...)
+	case rec.Level >= slog.LevelWarn:
+		h.logger.Warn(ctx, rec.Message, args...)
+	case rec.Level >= slog.LevelInfo:
+		h.logger.Info(ctx, rec.Message, args...)
+	default:
+		h.logger.Debug(ctx, rec.Message, args...)
+	}
+
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	out := h.clone()
+	last := &out.groups[len(out.groups)-1]
+	last.attrs = append(last.attrs[:len(last.attrs):len(last.attrs)], attrs...)
+
+	return out
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	out := h.clone()
+	out.groups = append(out.groups, handlerGroup{name: name})
+
+	return out
+}
+
+func (h *Handler) clone() *Handler {
+	return &Handler{
+		logger: h.logger,
+		groups: append([]handlerGroup(nil), h.groups...),
+	}
+}
+
+// nest puts attrs in the innermost group and wraps every group in its parent.
+func (h *Handler) nest(attrs []slog.Attr) []any {
+	for i := len(h.groups) - 1; i > 0; i-- {
+		g := h.groups[i]
+
+		inner := make([]any, 0, len(g.attrs)+len(attrs))
+		for _, attr := range append(g.attrs[:len(g.attrs):len(g.attrs)], attrs...) {
+			inner = append(inner, attr)
+		}
+
+		attrs = []slog.Attr{slog.Group(g.name, inner...)}
+	}
+
+	out := make([]any, 0, len(h.groups[0].attrs)+len(attrs))
+
+	for _, attr := range h.groups[0].attrs {
+		out = append(out, attr)
+	}
+
+	for _, attr := range attrs {
+		out = append(out, attr)
+	}
+
+	return out
+}
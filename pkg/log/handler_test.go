@@ -0,0 +1,82 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf    bytes.Buffer
+		logger = log.NewLogger(log.WithWriter(&buf), log.WithLevel(slog.LevelInfo))
+		ctx    = log.ContextWithAttrs(context.Background(), slog.String("requestID", "abc"))
+	)
+
+	sl := slog.New(log.NewHandler(logger)).
+		With("lib", "thirdparty").
+		WithGroup("http").
+		With("method", "GET")
+
+	sl.DebugContext(ctx, "dropped")
+	sl.WarnContext(ctx, "slow request", slog.Int("status", 200))
+
+	got := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	require.Equal(t, "WARN", got["level"])
+	require.Equal(t, "slow request", got["msg"])
+	require.Equal(t, "thirdparty", got["lib"])
+	require.Equal(t, "abc", got["requestID"])
+	require.Equal(t, map[string]any{"method": "GET", "status": float64(200)}, got["http"])
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	ctx := log.WithContext(context.Background(), log.NewLogger(log.WithWriter(&buf)).With("component", "orders"))
+	ctx = log.ContextWithAttrs(ctx, slog.String("route", "/orders"))
+
+	log.FromContext(ctx).Info(ctx, "hello")
+
+	got := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	require.Equal(t, "orders", got["component"])
+	require.Equal(t, "/orders", got["route"])
+
+	require.NotNil(t, log.FromContext(context.Background()))
+}
+
+// not parallel: it replaces the slog default logger
+func TestFromContext_default(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	var (
+		buf    bytes.Buffer
+		logger = log.NewLogger(log.WithWriter(&buf))
+		ctx    = log.ContextWithAttrs(context.Background(), slog.String("route", "/orders"))
+	)
+
+	slog.SetDefault(slog.New(log.NewHandler(logger)).With("component", "orders"))
+
+	log.FromContext(ctx).Info(ctx, "hello")
+
+	// a map would hide a duplicated key
+	require.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"route"`)))
+
+	got := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	require.Equal(t, "orders", got["component"])
+	require.Equal(t, "/orders", got["route"])
+}
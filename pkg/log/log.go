@@ -3,43 +3,181 @@ package log
 import (
 	"context"
 	"go.opentelemetry.io/otel/trace"
+	"io"
 	"log/slog"
 	"os"
 )
 
 type Logger interface {
+	Debug(ctx context.Context, msg string, args ...any)
 	Info(ctx context.Context, msg string, args ...any)
+	Warn(ctx context.Context, msg string, args ...any)
 	Error(ctx context.Context, msg string, args ...any)
 	With(args ...any) Logger
 }
 
-var _ Logger = (*JSONLogger)(nil)
+type Format string
 
-type JSONLogger struct {
-	logger *slog.Logger
+const (
+	FormatJSON Format = "json"
+	// FormatText is slog's key=value format, easier to read in a terminal.
+	FormatText Format = "text"
+)
+
+type options struct {
+	level      slog.Leveler
+	writer     io.Writer
+	format     Format
+	redactKeys []string
+	sampling   *SamplingOptions
+	spanLevel  slog.Leveler
 }
 
-func NewLogger() *JSONLogger {
-	return &JSONLogger{
-		logger: slog.New(slog.NewJSONHandler(os.Stderr, nil)),
+type Option func(o *options)
+
+// WithLevel sets the minimum level logged. Pass a *slog.LevelVar to change it
+// while the logger is in use.
+func WithLevel(level slog.Leveler) Option {
+	return func(o *options) {
+		o.level = level
 	}
 }
 
-func (l JSONLogger) With(args ...any) Logger {
-	return &JSONLogger{
-		logger: l.logger.With(args...),
+func WithWriter(w io.Writer) Option {
+	return func(o *options) {
+		o.writer = w
 	}
 }
 
-func (l JSONLogger) Info(ctx context.Context, msg string, args ...any) {
-	l.logger.InfoContext(ctx, msg, l.withTrace(ctx, args)...)
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+var _ Logger = (*SlogLogger)(nil)
+
+// JSONLogger is the former name of SlogLogger.
+//
+// Deprecated: Use SlogLogger, which logs text too.
+type JSONLogger = SlogLogger
+
+type SlogLogger struct {
+	logger    *slog.Logger
+	redactor  *redactor
+	spanLevel slog.Leveler
+	// attrs added by With, kept for the span events
+	attrs []slog.Attr
 }
 
-func (l JSONLogger) Error(ctx context.Context, msg string, args ...any) {
-	l.logger.ErrorContext(ctx, msg, l.withTrace(ctx, args)...)
+// NewLogger logs JSON to stderr at info level unless told otherwise. Values
+// of sensitive keys and tagged struct fields are always redacted.
+func NewLogger(opts ...Option) *SlogLogger {
+	o := options{
+		level:      slog.LevelInfo,
+		writer:     os.Stderr,
+		format:     FormatJSON,
+		redactKeys: DefaultRedactKeys,
+		spanLevel:  slog.LevelError,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	redactor := newRedactor(o.redactKeys)
+
+	handlerOpts := &slog.HandlerOptions{
+		Level:       o.level,
+		ReplaceAttr: redactor.replaceAttr,
+	}
+
+	var handler slog.Handler = slog.NewJSONHandler(o.writer, handlerOpts)
+	if o.format == FormatText {
+		handler = slog.NewTextHandler(o.writer, handlerOpts)
+	}
+
+	if o.sampling != nil {
+		handler = NewSamplingHandler(handler, *o.sampling)
+	}
+
+	return &SlogLogger{
+		logger:    slog.New(handler),
+		redactor:  redactor,
+		spanLevel: o.spanLevel,
+	}
+}
+
+func (l SlogLogger) With(args ...any) Logger {
+	rec := slog.Record{}
+	rec.Add(args...)
+
+	attrs := l.attrs[:len(l.attrs):len(l.attrs)]
+
+	rec.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+
+		return true
+	})
+
+	return &SlogLogger{
+		logger:    l.logger.With(args...),
+		redactor:  l.redactor,
+		spanLevel: l.spanLevel,
+		attrs:     attrs,
+	}
 }
 
-func (l JSONLogger) withTrace(ctx context.Context, args []any) []any {
+// Enabled reports whether a record at level would be logged.
+func (l SlogLogger) Enabled(ctx context.Context, level slog.Level) bool {
+	return l.logger.Enabled(ctx, level)
+}
+
+func (l SlogLogger) Debug(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelDebug, msg, args)
+}
+
+func (l SlogLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelInfo, msg, args)
+}
+
+func (l SlogLogger) Warn(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelWarn, msg, args)
+}
+
+func (l SlogLogger) Error(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelError, msg, args)
+}
+
+func (l SlogLogger) log(ctx context.Context, level slog.Level, msg string, args []any) {
+	// skip collecting the trace attrs when the record would be dropped anyway
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	args = l.withContextAttrs(ctx, args)
+
+	l.addSpanEvent(ctx, level, msg, args)
+	l.logger.Log(ctx, level, msg, l.withTrace(ctx, args)...)
+}
+
+func (l SlogLogger) withContextAttrs(ctx context.Context, args []any) []any {
+	attrs := AttrsFromContext(ctx)
+	if len(attrs) == 0 {
+		return args
+	}
+
+	out := make([]any, 0, len(args)+len(attrs))
+	out = append(out, args...)
+
+	for _, attr := range attrs {
+		out = append(out, attr)
+	}
+
+	return out
+}
+
+func (l SlogLogger) withTrace(ctx context.Context, args []any) []any {
 	spanCtx := trace.SpanContextFromContext(ctx)
 
 	if spanCtx.TraceID().IsValid() {
@@ -53,10 +191,6 @@ func (l JSONLogger) withTrace(ctx context.Context, args []any) []any {
 	return args
 }
 
-func Error(err error) slog.Attr {
-	return slog.String("error", err.Error())
-}
-
 var _ Logger = (*NopLogger)(nil)
 
 type NopLogger struct{}
@@ -69,6 +203,10 @@ func NewNopLogger() *NopLogger {
 	return &NopLogger{}
 }
 
+func (l NopLogger) Debug(ctx context.Context, msg string, args ...any) {}
+
 func (l NopLogger) Info(ctx context.Context, msg string, args ...any) {}
 
+func (l NopLogger) Warn(ctx context.Context, msg string, args ...any) {}
+
 func (l NopLogger) Error(ctx context.Context, msg string, args ...any) {}
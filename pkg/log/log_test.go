@@ -0,0 +1,83 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_level(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf    bytes.Buffer
+		level  = new(slog.LevelVar)
+		logger = log.NewLogger(log.WithWriter(&buf), log.WithLevel(level))
+		ctx    = context.Background()
+	)
+
+	logger.Debug(ctx, "debug")
+	logger.Info(ctx, "info")
+	logger.Warn(ctx, "warn")
+	logger.Error(ctx, "error")
+
+	level.Set(slog.LevelDebug)
+	logger.Debug(ctx, "debug again")
+
+	var got []string
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		rec := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+
+		got = append(got, rec["level"].(string)+" "+rec["msg"].(string))
+	}
+
+	require.Equal(t, []string{"INFO info", "WARN warn", "ERROR error", "DEBUG debug again"}, got)
+}
+
+func TestNewLogger_textFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	log.NewLogger(log.WithWriter(&buf), log.WithFormat(log.FormatText)).
+		Info(context.Background(), "hello", slog.String("k", "v"))
+
+	require.Contains(t, buf.String(), "level=INFO msg=hello k=v")
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(log.EnvLevel, "DEBUG")
+	t.Setenv(log.EnvFormat, "text")
+
+	var (
+		buf   bytes.Buffer
+		level = new(slog.LevelVar)
+	)
+
+	opts, err := log.FromEnv(level)
+	require.NoError(t, err)
+	require.Equal(t, slog.LevelDebug, level.Level())
+
+	log.NewLogger(append(opts, log.WithWriter(&buf))...).Debug(context.Background(), "hello")
+	require.Contains(t, buf.String(), "level=DEBUG msg=hello")
+}
+
+func TestFromEnv_invalid(t *testing.T) {
+	t.Setenv(log.EnvLevel, "loud")
+
+	_, err := log.FromEnv(new(slog.LevelVar))
+	require.ErrorContains(t, err, log.EnvLevel)
+
+	t.Setenv(log.EnvLevel, "")
+	t.Setenv(log.EnvFormat, "xml")
+
+	_, err = log.FromEnv(new(slog.LevelVar))
+	require.ErrorContains(t, err, log.EnvFormat)
+}
@@ -0,0 +1,233 @@
+package log
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+const (
+	// Redacted replaces the values of sensitive attrs and fields.
+	Redacted = "[REDACTED]"
+
+	redactTag      = "log"
+	redactMask     = "redact"
+	redactHash     = "hash"
+	redactOmit     = "-"
+	maxRedactDepth = 10
+)
+
+// DefaultRedactKeys are matched against attr keys, map keys and field names,
+// ignoring case, '-', '_' and '.'; any key containing one of them is redacted.
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "token", "apikey", "authorization", "cookie", "privatekey", "credential",
+}
+
+// WithRedactKeys redacts the values of keys containing any of patterns, in
+// addition to DefaultRedactKeys.
+func WithRedactKeys(patterns ...string) Option {
+	return func(o *options) {
+		o.redactKeys = append(o.redactKeys, patterns...)
+	}
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// redactor masks sensitive values before a handler writes them. Struct fields
+// opt in with a tag:
+//
+//	Password string `log:"redact"` // replaced by Redacted
+//	Customer string `log:"hash"`   // replaced by a hash, so equal values can still be correlated
+//	Internal string `log:"-"`      // left out
+type redactor struct {
+	patterns []string
+}
+
+func newRedactor(patterns []string) *redactor {
+	r := &redactor{}
+
+	for _, p := range patterns {
+		if p = normalizeKey(p); p != "" {
+			r.patterns = append(r.patterns, p)
+		}
+	}
+
+	return r
+}
+
+// replaceAttr has the signature of slog.HandlerOptions.ReplaceAttr.
+func (r *redactor) replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	for _, group := range groups {
+		if r.sensitive(group) {
+			return slog.String(attr.Key, Redacted)
+		}
+	}
+
+	if r.sensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	if attr.Value.Kind() == slog.KindAny {
+		attr.Value = slog.AnyValue(r.redact(reflect.ValueOf(attr.Value.Any()), 0))
+	}
+
+	return attr
+}
+
+func (r *redactor) sensitive(key string) bool {
+	key = normalizeKey(key)
+
+	for _, p := range r.patterns {
+		if strings.Contains(key, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redact returns v with its sensitive parts masked. Structs become maps keyed
+// by their JSON names so they are encoded the same way as before.
+func (r *redactor) redact(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if depth > maxRedactDepth {
+		return Redacted
+	}
+
+	t := v.Type()
+
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return v.Interface()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return r.redact(v.Elem(), depth+1)
+	case reflect.Struct:
+		return r.redactStruct(v, depth)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || v.IsNil() {
+			return v.Interface()
+		}
+
+		out := make(map[string]any, v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+
+			if r.sensitive(key) {
+				out[key] = Redacted
+
+				continue
+			}
+
+			out[key] = r.redact(iter.Value(), depth+1)
+		}
+
+		return out
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+
+		if t.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = r.redact(v.Index(i), depth+1)
+		}
+
+		return out
+	}
+
+	if v.CanInterface() {
+		return v.Interface()
+	}
+
+	return nil
+}
+
+func (r *redactor) redactStruct(v reflect.Value, depth int) any {
+	t := v.Type()
+	out := make(map[string]any, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+
+		value := v.Field(i)
+
+		if omitEmpty && value.IsZero() {
+			continue
+		}
+
+		switch tag := field.Tag.Get(redactTag); {
+		case tag == redactOmit:
+			continue
+		case tag == redactMask, r.sensitive(name), r.sensitive(field.Name):
+			out[name] = Redacted
+		case tag == redactHash:
+			out[name] = hashValue(value)
+		default:
+			out[name] = r.redact(value, depth+1)
+		}
+	}
+
+	return out
+}
+
+func jsonField(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, strings.Contains(opts, "omitempty"), false
+}
+
+func hashValue(v reflect.Value) string {
+	b, _ := json.Marshal(v.Interface())
+	sum := sha256.Sum256(b)
+
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '.', ' ':
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(key))
+}
@@ -0,0 +1,155 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+const secret = "s3cr3t-value"
+
+type address struct {
+	Street string `json:"street" log:"redact"`
+	City   string `json:"city"`
+}
+
+type payload struct {
+	Quantity  int               `json:"quantity"`
+	APIKey    string            `json:"apiKey"`
+	Customer  string            `json:"customerRef" log:"hash"`
+	Internal  string            `json:"internal" log:"-"`
+	Address   *address          `json:"address,omitempty"`
+	Headers   map[string]string `json:"headers"`
+	Cards     []address         `json:"cards"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+func TestNewLogger_redaction(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		opts []log.Option
+		log  func(ctx context.Context, logger log.Logger)
+		want map[string]any
+	}{
+		{
+			name: "struct tags and field names",
+			log: func(ctx context.Context, logger log.Logger) {
+				logger.Info(ctx, "msg", slog.Any("payload", &payload{
+					Quantity:  10,
+					APIKey:    secret,
+					Customer:  "customer-1",
+					Internal:  secret,
+					Address:   &address{Street: secret, City: "Bucharest"},
+					Headers:   map[string]string{"Authorization": secret, "Accept": "application/json"},
+					Cards:     []address{{Street: secret, City: "Cluj"}},
+					CreatedAt: createdAt,
+				}))
+			},
+			want: map[string]any{
+				"payload": map[string]any{
+					"quantity":    float64(10),
+					"apiKey":      log.Redacted,
+					"customerRef": "sha256:ba105343527e0c97",
+					"address":     map[string]any{"street": log.Redacted, "city": "Bucharest"},
+					"headers":     map[string]any{"Authorization": log.Redacted, "Accept": "application/json"},
+					"cards":       []any{map[string]any{"street": log.Redacted, "city": "Cluj"}},
+					"createdAt":   "2024-01-02T03:04:05Z",
+				},
+			},
+		},
+		{
+			name: "attr keys",
+			log: func(ctx context.Context, logger log.Logger) {
+				logger.Info(ctx, "msg",
+					slog.String("password", secret),
+					slog.String("X-Api-Key", secret),
+					slog.Group("credentials", slog.String("user", secret)),
+					slog.String("user", "bob"),
+				)
+			},
+			want: map[string]any{
+				"password":    log.Redacted,
+				"X-Api-Key":   log.Redacted,
+				"credentials": map[string]any{"user": log.Redacted},
+				"user":        "bob",
+			},
+		},
+		{
+			name: "context attrs and With",
+			log: func(ctx context.Context, logger log.Logger) {
+				ctx = log.ContextWithAttrs(ctx, slog.String("session_token", secret))
+				logger.With(slog.String("client_secret", secret)).Info(ctx, "msg")
+			},
+			want: map[string]any{
+				"session_token": log.Redacted,
+				"client_secret": log.Redacted,
+			},
+		},
+		{
+			name: "custom keys",
+			opts: []log.Option{log.WithRedactKeys("iban")},
+			log: func(ctx context.Context, logger log.Logger) {
+				logger.Info(ctx, "msg", slog.String("payer_IBAN", secret), slog.String("token", secret))
+			},
+			want: map[string]any{
+				"payer_IBAN": log.Redacted,
+				"token":      log.Redacted,
+			},
+		},
+		{
+			name: "slog handler",
+			log: func(ctx context.Context, logger log.Logger) {
+				slog.New(log.NewHandler(logger)).InfoContext(ctx, "msg", "refresh_token", secret)
+			},
+			want: map[string]any{
+				"refresh_token": log.Redacted,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			tt.log(context.Background(), log.NewLogger(append(tt.opts, log.WithWriter(&buf))...))
+
+			require.NotContains(t, buf.String(), secret)
+
+			got := map[string]any{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+			for _, key := range []string{"time", "level", "msg"} {
+				delete(got, key)
+			}
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewLogger_redactionTextFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	log.NewLogger(log.WithWriter(&buf), log.WithFormat(log.FormatText)).Info(context.Background(), "msg",
+		slog.String("authorization", secret),
+		slog.Any("payload", payload{APIKey: secret}),
+	)
+
+	require.NotContains(t, buf.String(), secret)
+	require.Contains(t, buf.String(), "authorization="+log.Redacted)
+}
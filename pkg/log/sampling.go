@@ -0,0 +1,203 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSamplingInterval   = time.Second
+	DefaultSamplingFirst      = 100
+	DefaultSamplingThereafter = 100
+
+	droppedMessage = "log records dropped"
+)
+
+// SamplingOptions configure a SamplingHandler. Records are counted per level
+// and message; no message is exempt.
+type SamplingOptions struct {
+	// Interval is how long counts are kept for. Defaults to
+	// DefaultSamplingInterval.
+	Interval time.Duration
+	// First records per key are kept every interval. Defaults to
+	// DefaultSamplingFirst.
+	First int
+	// Thereafter keeps one in Thereafter of the following records. Defaults to
+	// DefaultSamplingThereafter; a negative value drops them all.
+	Thereafter int
+	// Always is the level from which records are never dropped. Defaults to
+	// slog.LevelError.
+	Always slog.Leveler
+}
+
+// WithSampling wraps the logger's handler in a SamplingHandler.
+func WithSampling(opts SamplingOptions) Option {
+	return func(o *options) {
+		o.sampling = &opts
+	}
+}
+
+var _ slog.Handler = (*SamplingHandler)(nil)
+
+// SamplingHandler keeps bursts of identical records from flooding the output.
+// What it drops is summarised in a "log records dropped" warning written
+// through next once the interval of the first drop is over.
+type SamplingHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+// sampler is shared by a SamplingHandler and the handlers derived from it.
+type sampler struct {
+	opts SamplingOptions
+	// root receives the summaries, without the attrs and groups of any
+	// derived handler
+	root slog.Handler
+
+	mu          sync.Mutex
+	windowStart time.Time
+	seen        map[samplingKey]int
+	dropped     map[samplingKey]int
+	flushTimer  *time.Timer
+}
+
+func NewSamplingHandler(next slog.Handler, opts SamplingOptions) *SamplingHandler {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultSamplingInterval
+	}
+
+	if opts.First <= 0 {
+		opts.First = DefaultSamplingFirst
+	}
+
+	if opts.Thereafter == 0 {
+		opts.Thereafter = DefaultSamplingThereafter
+	}
+
+	if opts.Always == nil {
+		opts.Always = slog.LevelError
+	}
+
+	return &SamplingHandler{
+		next: next,
+		sampler: &sampler{
+			opts:    opts,
+			root:    next,
+			seen:    make(map[samplingKey]int),
+			dropped: make(map[samplingKey]int),
+		},
+	}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if !h.sampler.keep(rec) {
+		return nil
+	}
+
+	return h.next.Handle(ctx, rec)
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{
+		next:    h.next.WithAttrs(attrs),
+		sampler: h.sampler,
+	}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{
+		next:    h.next.WithGroup(name),
+		sampler: h.sampler,
+	}
+}
+
+func (s *sampler) keep(rec slog.Record) bool {
+	if rec.Level >= s.opts.Always.Level() {
+		return true
+	}
+
+	key := samplingKey{level: rec.Level, msg: rec.Message}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.windowStart) >= s.opts.Interval {
+		s.windowStart = now
+		clear(s.seen)
+	}
+
+	s.seen[key]++
+
+	n := s.seen[key] - s.opts.First
+	if n <= 0 || (s.opts.Thereafter > 0 && n%s.opts.Thereafter == 0) {
+		return true
+	}
+
+	s.dropped[key]++
+
+	if s.flushTimer == nil {
+		s.flushTimer = time.AfterFunc(s.opts.Interval, s.flush)
+	}
+
+	return false
+}
+
+type droppedRecords struct {
+	Level   string `json:"level"`
+	Msg     string `json:"msg"`
+	Dropped int    `json:"dropped"`
+}
+
+// flush writes the summary of the records dropped since the previous one.
+func (s *sampler) flush() {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = make(map[samplingKey]int)
+	s.flushTimer = nil
+	s.mu.Unlock()
+
+	var (
+		total   int
+		records = make([]droppedRecords, 0, len(dropped))
+	)
+
+	for key, n := range dropped {
+		total += n
+		records = append(records, droppedRecords{Level: key.level.String(), Msg: key.msg, Dropped: n})
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Dropped != records[j].Dropped {
+			return records[i].Dropped > records[j].Dropped
+		}
+
+		return records[i].Msg < records[j].Msg
+	})
+
+	ctx := context.Background()
+	if !s.root.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+
+	rec := slog.NewRecord(time.Now(), slog.LevelWarn, droppedMessage, 0)
+	rec.AddAttrs(
+		slog.Int("dropped", total),
+		slog.Duration("interval", s.opts.Interval),
+		slog.Any("records", records),
+	)
+
+	_ = s.root.Handle(ctx, rec)
+}
@@ -0,0 +1,180 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is written to by the summary timer while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		rec := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+
+		out = append(out, rec)
+	}
+
+	return out
+}
+
+func TestNewLogger_sampling(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf    syncBuffer
+		ctx    = context.Background()
+		logger = log.NewLogger(log.WithWriter(&buf), log.WithSampling(log.SamplingOptions{
+			Interval:   200 * time.Millisecond,
+			First:      3,
+			Thereafter: 5,
+			Always:     slog.LevelError,
+		}))
+		derived = logger.With(slog.String("component", "orders"))
+	)
+
+	for i := 0; i < 10; i++ {
+		logger.Info(ctx, "burst", slog.Int("i", i))
+		derived.Info(ctx, "burst", slog.Int("i", i))
+		logger.Error(ctx, "failed")
+	}
+
+	logger.Warn(ctx, "other")
+
+	counts := map[string]int{}
+	for _, rec := range buf.records(t) {
+		counts[rec["msg"].(string)]++
+	}
+
+	// 20 bursts: the first 3, then the 8th, 13th and 18th
+	require.Equal(t, map[string]int{"burst": 6, "failed": 10, "other": 1}, counts)
+
+	var summary map[string]any
+
+	require.Eventually(t, func() bool {
+		for _, rec := range buf.records(t) {
+			if rec["msg"] == "log records dropped" {
+				summary = rec
+
+				return true
+			}
+		}
+
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, "WARN", summary["level"])
+	require.Equal(t, float64(14), summary["dropped"])
+	require.Equal(t, []any{map[string]any{"level": "INFO", "msg": "burst", "dropped": float64(14)}}, summary["records"])
+	require.NotContains(t, summary, "component")
+}
+
+func TestNewLogger_samplingNewInterval(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf    syncBuffer
+		ctx    = context.Background()
+		logger = log.NewLogger(log.WithWriter(&buf), log.WithSampling(log.SamplingOptions{
+			Interval: 50 * time.Millisecond,
+			First:    1,
+		}))
+	)
+
+	logger.Info(ctx, "tick")
+	logger.Info(ctx, "tick")
+
+	time.Sleep(60 * time.Millisecond)
+
+	logger.Info(ctx, "tick")
+
+	var ticks int
+
+	for _, rec := range buf.records(t) {
+		if rec["msg"] == "tick" {
+			ticks++
+		}
+	}
+
+	require.Equal(t, 2, ticks)
+}
+
+func TestNewLogger_samplingDefaults(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf    syncBuffer
+		ctx    = context.Background()
+		logger = log.NewLogger(log.WithWriter(&buf), log.WithSampling(log.SamplingOptions{
+			Interval: time.Hour,
+			First:    1,
+		}))
+	)
+
+	for i := 0; i < log.DefaultSamplingThereafter+1; i++ {
+		logger.Info(ctx, "burst")
+		logger.Error(ctx, "failed")
+	}
+
+	counts := map[string]int{}
+	for _, rec := range buf.records(t) {
+		counts[rec["msg"].(string)]++
+	}
+
+	// errors are never dropped; the bursts keep the first and the 101st
+	require.Equal(t, map[string]int{"burst": 2, "failed": log.DefaultSamplingThereafter + 1}, counts)
+}
+
+func TestFromEnv_sampling(t *testing.T) {
+	t.Setenv(log.EnvSamplingFirst, "1")
+	t.Setenv(log.EnvSamplingThereafter, "0")
+	t.Setenv(log.EnvSamplingInterval, "1h")
+	t.Setenv(log.EnvSamplingAlways, "warn")
+
+	var buf syncBuffer
+
+	opts, err := log.FromEnv(new(slog.LevelVar))
+	require.NoError(t, err)
+
+	logger := log.NewLogger(append(opts, log.WithWriter(&buf))...)
+
+	for i := 0; i < 3; i++ {
+		logger.Info(context.Background(), "info")
+		logger.Warn(context.Background(), "warn")
+	}
+
+	require.Len(t, buf.records(t), 4)
+
+	t.Setenv(log.EnvSamplingFirst, "none")
+
+	_, err = log.FromEnv(new(slog.LevelVar))
+	require.ErrorContains(t, err, log.EnvSamplingFirst)
+}
@@ -0,0 +1,112 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"time"
+)
+
+// WithSpanEvents sets the level from which records logged while a span is
+// recording are added to it as events; error records also set the span
+// status. Defaults to slog.LevelError.
+func WithSpanEvents(level slog.Leveler) Option {
+	return func(o *options) {
+		o.spanLevel = level
+	}
+}
+
+// addSpanEvent mirrors a record on the span in ctx, attrs redacted the same
+// way as in the output.
+func (l SlogLogger) addSpanEvent(ctx context.Context, level slog.Level, msg string, args []any) {
+	if l.spanLevel == nil || level < l.spanLevel.Level() {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	rec := slog.NewRecord(time.Time{}, level, msg, 0)
+	rec.AddAttrs(l.attrs...)
+	rec.Add(args...)
+
+	kvs := []attribute.KeyValue{attribute.String("log.severity", level.String())}
+
+	rec.Attrs(func(attr slog.Attr) bool {
+		kvs = l.appendSpanAttr(kvs, nil, attr)
+
+		return true
+	})
+
+	span.AddEvent(msg, trace.WithAttributes(kvs...))
+
+	if level >= slog.LevelError {
+		span.SetStatus(codes.Error, msg)
+	}
+}
+
+func (l SlogLogger) appendSpanAttr(kvs []attribute.KeyValue, groups []string, attr slog.Attr) []attribute.KeyValue {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+
+		for _, a := range attr.Value.Group() {
+			kvs = l.appendSpanAttr(kvs, groups, a)
+		}
+
+		return kvs
+	}
+
+	if l.redactor != nil {
+		attr = l.redactor.replaceAttr(groups, attr)
+	}
+
+	if attr.Equal(slog.Attr{}) {
+		return kvs
+	}
+
+	key := attr.Key
+	for i := len(groups) - 1; i >= 0; i-- {
+		key = groups[i] + "." + key
+	}
+
+	return append(kvs, spanAttr(key, attr.Value))
+}
+
+func spanAttr(key string, v slog.Value) attribute.KeyValue {
+	switch v.Kind() {
+	case slog.KindString:
+		return attribute.String(key, v.String())
+	case slog.KindInt64:
+		return attribute.Int64(key, v.Int64())
+	case slog.KindUint64:
+		return attribute.Int64(key, int64(v.Uint64()))
+	case slog.KindFloat64:
+		return attribute.Float64(key, v.Float64())
+	case slog.KindBool:
+		return attribute.Bool(key, v.Bool())
+	case slog.KindDuration:
+		return attribute.String(key, v.Duration().String())
+	case slog.KindTime:
+		return attribute.String(key, v.Time().Format(time.RFC3339Nano))
+	}
+
+	if err, ok := v.Any().(error); ok {
+		return attribute.String(key, err.Error())
+	}
+
+	if b, err := json.Marshal(v.Any()); err == nil {
+		return attribute.String(key, string(b))
+	}
+
+	return attribute.String(key, fmt.Sprint(v.Any()))
+}
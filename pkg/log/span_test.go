@@ -0,0 +1,95 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestNewLogger_spanEvents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		opts       []log.Option
+		wantEvents []string
+	}{
+		{
+			name:       "errors only by default",
+			wantEvents: []string{"create order failed"},
+		},
+		{
+			name:       "from info",
+			opts:       []log.Option{log.WithSpanEvents(slog.LevelInfo)},
+			wantEvents: []string{"solving", "create order failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				recorder = tracetest.NewSpanRecorder()
+				tracer   = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+				logger   = log.NewLogger(append(tt.opts, log.WithWriter(io.Discard))...).
+						With(slog.String("component", "orders"))
+			)
+
+			ctx, span := tracer.Start(context.Background(), "request")
+			ctx = log.ContextWithAttrs(ctx, slog.String("requestID", "req-1"))
+
+			logger.Debug(ctx, "debug")
+			logger.Info(ctx, "solving", slog.Int("quantity", 250))
+			logger.Error(ctx, "create order failed",
+				slog.Group("payload", slog.Int("quantity", 250), slog.String("apiKey", "s3cr3t")),
+				log.Error(errors.New("boom")),
+			)
+
+			span.End()
+
+			got := recorder.Ended()[0]
+
+			var names []string
+			for _, event := range got.Events() {
+				names = append(names, event.Name)
+			}
+
+			require.Equal(t, tt.wantEvents, names)
+			require.Equal(t, codes.Error, got.Status().Code)
+			require.Equal(t, "create order failed", got.Status().Description)
+
+			attrs := map[attribute.Key]string{}
+			for _, kv := range got.Events()[len(got.Events())-1].Attributes {
+				attrs[kv.Key] = kv.Value.Emit()
+			}
+
+			require.Equal(t, map[attribute.Key]string{
+				"log.severity":     "ERROR",
+				"component":        "orders",
+				"requestID":        "req-1",
+				"payload.quantity": "250",
+				"payload.apiKey":   log.Redacted,
+				"error":            `{"msg":"boom","type":"*errors.errorString"}`,
+			}, attrs)
+		})
+	}
+}
+
+func TestNewLogger_spanEventsWithoutSpan(t *testing.T) {
+	t.Parallel()
+
+	require.NotPanics(t, func() {
+		log.NewLogger(log.WithWriter(io.Discard)).Error(context.Background(), "failed")
+	})
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Write renders every registered metric in the Prometheus text exposition
+// format, sorted by name and label values.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	bw := bufio.NewWriter(w)
+
+	for _, f := range families {
+		f.write(bw)
+	}
+
+	return bw.Flush()
+}
+
+func (f *family) write(w *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.help != "" {
+		w.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+	}
+
+	w.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		labels := formatLabels(f.labels, s.labelValues)
+
+		if f.typ != typeHistogram {
+			writeSample(w, f.name, labels, s.value)
+
+			continue
+		}
+
+		var cumulative uint64
+
+		for i, upper := range f.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, f.name+"_bucket", appendLabel(labels, "le", formatFloat(upper)), float64(cumulative))
+		}
+
+		writeSample(w, f.name+"_bucket", appendLabel(labels, "le", "+Inf"), float64(s.count))
+		writeSample(w, f.name+"_sum", labels, s.sum)
+		writeSample(w, f.name+"_count", labels, float64(s.count))
+	}
+}
+
+func writeSample(w *bufio.Writer, name, labels string, v float64) {
+	w.WriteString(name)
+
+	if labels != "" {
+		w.WriteString("{" + labels + "}")
+	}
+
+	w.WriteString(" " + formatFloat(v) + "\n")
+}
+
+func formatLabels(names, values []string) string {
+	var out string
+
+	for i, name := range names {
+		out = appendLabel(out, name, values[i])
+	}
+
+	return out
+}
+
+func appendLabel(labels, name, value string) string {
+	if labels != "" {
+		labels += ","
+	}
+
+	return labels + name + `="` + escapeLabelValue(value) + `"`
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
@@ -0,0 +1,188 @@
+// Package metrics is a minimal metrics registry rendered in the Prometheus
+// text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ContentType of the Prometheus text exposition format written by Registry.Write.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets suit latencies in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// ExponentialBuckets returns count buckets starting at start, each factor times
+// the previous one.
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	out := make([]float64, count)
+
+	for i := range out {
+		out[i] = start
+		start *= factor
+	}
+
+	return out
+}
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Counter registers a monotonically increasing value. It panics if name is
+// already registered.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{family: r.register(name, help, typeCounter, labels, nil)}
+}
+
+// Gauge registers a value which can go up and down. It panics if name is
+// already registered.
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{family: r.register(name, help, typeGauge, labels, nil)}
+}
+
+// Histogram registers a distribution counted in buckets, which must be sorted
+// in increasing order. It panics if name is already registered.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("metrics: buckets of %s aren't sorted", name))
+	}
+
+	return &Histogram{family: r.register(name, help, typeHistogram, labels, buckets)}
+}
+
+func (r *Registry) register(name, help, typ string, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.families[name]; ok {
+		panic(fmt.Sprintf("metrics: %s is already registered", name))
+	}
+
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.families[name] = f
+
+	return f
+}
+
+type Counter struct {
+	family *family
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add panics if v is negative.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("metrics: counter %s can't decrease", c.family.name))
+	}
+
+	c.family.update(labelValues, func(s *series) {
+		s.value += v
+	})
+}
+
+type Gauge struct {
+	family *family
+}
+
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.family.update(labelValues, func(s *series) {
+		s.value = v
+	})
+}
+
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.family.update(labelValues, func(s *series) {
+		s.value += v
+	})
+}
+
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+type Histogram struct {
+	family *family
+}
+
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.family.update(labelValues, func(s *series) {
+		if s.counts == nil {
+			s.counts = make([]uint64, len(h.family.buckets))
+		}
+
+		if i := sort.SearchFloat64s(h.family.buckets, v); i < len(s.counts) {
+			s.counts[i]++
+		}
+
+		s.sum += v
+		s.count++
+	})
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	// histograms only; counts aren't cumulative
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (f *family) update(labelValues []string, fn func(s *series)) {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values; got %d", f.name, len(f.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		f.series[key] = s
+	}
+
+	fn(s)
+}
@@ -0,0 +1,98 @@
+package metrics_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/metrics"
+	"sync"
+	"testing"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	t.Parallel()
+
+	r := metrics.NewRegistry()
+
+	requests := r.Counter("http_requests_total", "Requests served.", "method", "code")
+	requests.Inc("GET", "200")
+	requests.Add(2, "GET", "200")
+	requests.Inc("POST", "400")
+
+	inflight := r.Gauge("http_requests_in_flight", "Requests being served.")
+	inflight.Inc()
+	inflight.Inc()
+	inflight.Dec()
+
+	latency := r.Histogram("latency_seconds", "Latency with \"quotes\"\nand a newline.", []float64{0.1, 1}, "route")
+	latency.Observe(0.05, `/a"b`)
+	latency.Observe(0.1, `/a"b`)
+	latency.Observe(0.5, `/a"b`)
+	latency.Observe(2, `/a"b`)
+
+	r.Counter("unused_total", "")
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf))
+
+	want := `# HELP http_requests_in_flight Requests being served.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight 1
+# HELP http_requests_total Requests served.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",code="200"} 3
+http_requests_total{method="POST",code="400"} 1
+# HELP latency_seconds Latency with "quotes"\nand a newline.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a\"b",le="0.1"} 2
+latency_seconds_bucket{route="/a\"b",le="1"} 3
+latency_seconds_bucket{route="/a\"b",le="+Inf"} 4
+latency_seconds_sum{route="/a\"b"} 2.65
+latency_seconds_count{route="/a\"b"} 4
+# TYPE unused_total counter
+`
+	require.Equal(t, want, buf.String())
+}
+
+func TestRegistry_panics(t *testing.T) {
+	t.Parallel()
+
+	r := metrics.NewRegistry()
+	c := r.Counter("c_total", "", "label")
+
+	require.Panics(t, func() { r.Gauge("c_total", "") })
+	require.Panics(t, func() { c.Inc() })
+	require.Panics(t, func() { c.Add(-1, "v") })
+	require.Panics(t, func() { r.Histogram("h", "", []float64{2, 1}) })
+}
+
+func TestCounter_concurrent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		r  = metrics.NewRegistry()
+		c  = r.Counter("c_total", "")
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			c.Inc()
+		}()
+	}
+
+	wg.Wait()
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf))
+	require.Contains(t, buf.String(), "c_total 100\n")
+}
+
+func TestExponentialBuckets(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []float64{1, 2, 4, 8}, metrics.ExponentialBuckets(1, 2, 4))
+}
@@ -0,0 +1,104 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/openapi"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
+	"reflect"
+	"testing"
+)
+
+type Item struct {
+	Size int `json:"size" validate:"min=1"`
+}
+
+type Payload struct {
+	Name  string         `json:"name" validate:"required,max=5"`
+	Kind  string         `json:"kind,omitempty" validate:"oneof=small large"`
+	Items []Item         `json:"items" validate:"max=2"`
+	Tags  map[string]int `json:"tags,omitempty"`
+	Next  *Payload       `json:"next,omitempty"`
+}
+
+func TestDocument_SchemaFor(t *testing.T) {
+	t.Parallel()
+
+	doc := openapi.New(openapi.Info{Title: "test", Version: "1"})
+
+	got := doc.SchemaFor(reflect.TypeOf(Payload{}))
+	require.Equal(t, "#/components/schemas/openapi_test.Payload", got.Ref)
+
+	b, err := json.Marshal(doc.Components.Schemas)
+	require.NoError(t, err)
+
+	want := `{
+		"openapi_test.Item": {
+			"type": "object",
+			"properties": {"size": {"type": "integer", "minimum": 1}},
+			"additionalProperties": false
+		},
+		"openapi_test.Payload": {
+			"type": "object",
+			"properties": {
+				"items": {"type": "array", "items": {"$ref": "#/components/schemas/openapi_test.Item"}, "maxItems": 2},
+				"kind": {"type": "string", "enum": ["small", "large"]},
+				"name": {"type": "string", "maxLength": 5},
+				"next": {"$ref": "#/components/schemas/openapi_test.Payload"},
+				"tags": {"type": "object", "additionalProperties": {"type": "integer"}}
+			},
+			"required": ["name"],
+			"additionalProperties": false
+		}
+	}`
+
+	require.JSONEq(t, want, string(b))
+}
+
+func TestDocument_Validate(t *testing.T) {
+	t.Parallel()
+
+	doc := openapi.New(openapi.Info{Title: "test", Version: "1"})
+	schema := doc.SchemaFor(reflect.TypeOf(Payload{}))
+
+	tests := []struct {
+		name string
+		in   string
+		want error
+	}{
+		{
+			name: "valid",
+			in:   `{"name": "abc", "items": [{"size": 1}], "next": {"name": "x"}}`,
+		},
+		{
+			name: "not an object",
+			in:   `"abc"`,
+			want: validation.Errors{{Message: "must be an object; got string"}},
+		},
+		{
+			name: "invalid",
+			in:   `{"kind": "medium", "items": [{"size": 0}, {"size": 1.5}], "tags": {"a": "b"}, "extra": 1}`,
+			want: validation.Errors{
+				{Path: "name", Message: "is required"},
+				{Path: "extra", Message: "unknown field"},
+				{Path: "items[0].size", Message: "must be greater than or equal to 1; got 0"},
+				{Path: "items[1].size", Message: "must be an integer; got number"},
+				{Path: "kind", Message: "must be one of [small large]; got medium"},
+				{Path: "tags.a", Message: "must be an integer; got string"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var v any
+			require.NoError(t, json.Unmarshal([]byte(tt.in), &v))
+
+			require.Equal(t, tt.want, doc.Validate(schema, v))
+		})
+	}
+}
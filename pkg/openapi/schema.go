@@ -0,0 +1,167 @@
+package openapi
+
+import (
+	"encoding/xml"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const refPrefix = "#/components/schemas/"
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	xmlNameType = reflect.TypeOf(xml.Name{})
+)
+
+// SchemaFor returns the schema of t. Named structs are registered under
+// components and referenced, so each one is described once.
+func (d *Document) SchemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.SchemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.SchemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+
+		name := schemaName(t)
+
+		if _, ok := d.Components.Schemas[name]; !ok {
+			// placeholder first so that recursive types terminate
+			d.Components.Schemas[name] = &Schema{}
+			*d.Components.Schemas[name] = *d.structSchema(t)
+		}
+
+		return &Schema{Ref: refPrefix + name}
+	}
+
+	return &Schema{}
+}
+
+// Resolve follows a $ref to its component schema.
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, refPrefix)]
+	}
+
+	return s
+}
+
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	out := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Type == xmlNameType {
+			continue
+		}
+
+		name := validation.FieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		prop := d.SchemaFor(field.Type)
+
+		for _, rule := range validation.Rules(field.Tag.Get("validate")) {
+			if rule.Name == "required" {
+				out.Required = append(out.Required, name)
+
+				continue
+			}
+
+			applyRule(prop, rule)
+		}
+
+		out.Properties[name] = prop
+	}
+
+	return out
+}
+
+func applyRule(s *Schema, rule validation.Rule) {
+	switch rule.Name {
+	case "min", "max":
+		f, err := strconv.ParseFloat(rule.Param, 64)
+		if err != nil {
+			return
+		}
+
+		n := int(f)
+
+		switch s.Type {
+		case "integer", "number":
+			if rule.Name == "min" {
+				s.Minimum = &f
+			} else {
+				s.Maximum = &f
+			}
+		case "string":
+			if rule.Name == "min" {
+				s.MinLength = &n
+			} else {
+				s.MaxLength = &n
+			}
+		case "array":
+			if rule.Name == "min" {
+				s.MinItems = &n
+			} else {
+				s.MaxItems = &n
+			}
+		}
+	case "oneof":
+		for _, v := range strings.Fields(rule.Param) {
+			s.Enum = append(s.Enum, enumValue(s.Type, v))
+		}
+	}
+}
+
+func enumValue(typ, v string) any {
+	switch typ {
+	case "integer", "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+
+	return v
+}
+
+func schemaName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+
+	if pkg == "" {
+		return t.Name()
+	}
+
+	return pkg + "." + t.Name()
+}
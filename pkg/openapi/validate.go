@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"fmt"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Validate checks a value decoded by encoding/json into an interface (maps,
+// slices, float64, string, bool and nil) against s.
+func (d *Document) Validate(s *Schema, v any) error {
+	var errs validation.Errors
+
+	d.validate(s, v, "", &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (d *Document) validate(s *Schema, v any, path string, errs *validation.Errors) {
+	s = d.Resolve(s)
+	if s == nil {
+		return
+	}
+
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, validation.FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Type != "" && !hasType(s.Type, v) {
+		fail("must be %s; got %s", article(s.Type), jsonType(v))
+
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		fail("must be one of %v; got %v", s.Enum, v)
+
+		return
+	}
+
+	switch v := v.(type) {
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("must be greater than or equal to %v; got %v", *s.Minimum, v)
+		}
+
+		if s.Maximum != nil && v > *s.Maximum {
+			fail("must be less than or equal to %v; got %v", *s.Maximum, v)
+		}
+	case string:
+		if n := len([]rune(v)); s.MinLength != nil && n < *s.MinLength {
+			fail("must be at least %d characters long; got %d", *s.MinLength, n)
+		} else if s.MaxLength != nil && n > *s.MaxLength {
+			fail("must be at most %d characters long; got %d", *s.MaxLength, n)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("must have at least %d items; got %d", *s.MinItems, len(v))
+		} else if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("must have at most %d items; got %d", *s.MaxItems, len(v))
+		}
+
+		for i, item := range v {
+			d.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case map[string]any:
+		d.validateObject(s, v, path, errs)
+	}
+}
+
+func (d *Document) validateObject(s *Schema, v map[string]any, path string, errs *validation.Errors) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+
+		return path + "." + key
+	}
+
+	for _, key := range s.Required {
+		if _, ok := v[key]; !ok {
+			*errs = append(*errs, validation.FieldError{Path: join(key), Message: "is required"})
+		}
+	}
+
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if prop, ok := s.Properties[key]; ok {
+			d.validate(prop, v[key], join(key), errs)
+
+			continue
+		}
+
+		switch additional := s.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				*errs = append(*errs, validation.FieldError{Path: join(key), Message: "unknown field"})
+			}
+		case *Schema:
+			d.validate(additional, v[key], join(key), errs)
+		}
+	}
+}
+
+func hasType(typ string, v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case float64:
+		return typ == "number" || (typ == "integer" && v == math.Trunc(v))
+	case string:
+		return typ == "string"
+	case []any:
+		return typ == "array"
+	case map[string]any:
+		return typ == "object"
+	}
+
+	return false
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+func article(typ string) string {
+	if strings.IndexAny(typ[:1], "aeiou") == 0 {
+		return "an " + typ
+	}
+
+	return "a " + typ
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package requestid_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/requestid"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	a, b := requestid.New(), requestid.New()
+
+	require.Len(t, a, 32)
+	require.NotEqual(t, a, b)
+	require.True(t, requestid.Valid(a))
+}
+
+func TestValid(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, requestid.Valid("req-1_2.3:4"))
+	require.False(t, requestid.Valid(""))
+	require.False(t, requestid.Valid("a b"))
+	require.False(t, requestid.Valid("a\nb"))
+	require.False(t, requestid.Valid(strings.Repeat("a", 129)))
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, requestid.FromContext(context.Background()))
+	require.Equal(t, "id", requestid.FromContext(requestid.NewContext(context.Background(), "id")))
+}
@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Samplers, named as in the OpenTelemetry OTEL_TRACES_SAMPLER variable.
+const (
+	SamplerAlwaysOn                = "always_on"
+	SamplerAlwaysOff               = "always_off"
+	SamplerTraceIDRatio            = "traceidratio"
+	SamplerParentBasedAlwaysOn     = "parentbased_always_on"
+	SamplerParentBasedAlwaysOff    = "parentbased_always_off"
+	SamplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+)
+
+const (
+	EnvServiceName        = "OTEL_SERVICE_NAME"
+	EnvServiceVersion     = "OTEL_SERVICE_VERSION"
+	EnvEnvironment        = "OTEL_DEPLOYMENT_ENVIRONMENT"
+	EnvExporter           = "OTEL_TRACES_EXPORTER"
+	EnvFile               = "OTEL_TRACES_FILE"
+	EnvSampler            = "OTEL_TRACES_SAMPLER"
+	EnvSamplerArg         = "OTEL_TRACES_SAMPLER_ARG"
+	EnvBatchTimeout       = "OTEL_BSP_SCHEDULE_DELAY"
+	EnvExportTimeout      = "OTEL_BSP_EXPORT_TIMEOUT"
+	EnvMaxQueueSize       = "OTEL_BSP_MAX_QUEUE_SIZE"
+	EnvMaxExportBatchSize = "OTEL_BSP_MAX_EXPORT_BATCH_SIZE"
+)
+
+// Config of the tracer provider. Zero batching values fall back to the SDK
+// defaults.
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+
+	Exporter string
+	// FilePath is where ExporterFile appends spans.
+	FilePath string
+
+	Sampler      string
+	SamplerRatio float64
+
+	BatchTimeout       time.Duration
+	ExportTimeout      time.Duration
+	MaxQueueSize       int
+	MaxExportBatchSize int
+}
+
+// FromEnv reads the configuration from the environment, following the
+// OpenTelemetry variable names where one exists:
+//
+//	OTEL_SERVICE_NAME, OTEL_SERVICE_VERSION, OTEL_DEPLOYMENT_ENVIRONMENT
+//	OTEL_TRACES_EXPORTER=none|stdout|file, OTEL_TRACES_FILE
+//	OTEL_TRACES_SAMPLER, OTEL_TRACES_SAMPLER_ARG
+//	OTEL_BSP_SCHEDULE_DELAY, OTEL_BSP_EXPORT_TIMEOUT (milliseconds)
+//	OTEL_BSP_MAX_QUEUE_SIZE, OTEL_BSP_MAX_EXPORT_BATCH_SIZE
+//
+// serviceName is used when OTEL_SERVICE_NAME isn't set. Spans are only
+// exported when OTEL_TRACES_EXPORTER is set.
+func FromEnv(serviceName string) (Config, error) {
+	cfg := Config{
+		ServiceName:    serviceName,
+		ServiceVersion: os.Getenv(EnvServiceVersion),
+		Environment:    os.Getenv(EnvEnvironment),
+		Exporter:       ExporterNone,
+		FilePath:       os.Getenv(EnvFile),
+		Sampler:        SamplerParentBasedAlwaysOn,
+		SamplerRatio:   1,
+	}
+
+	if v := os.Getenv(EnvServiceName); v != "" {
+		cfg.ServiceName = v
+	}
+
+	if v := os.Getenv(EnvExporter); v != "" {
+		cfg.Exporter = v
+	}
+
+	if v := os.Getenv(EnvSampler); v != "" {
+		cfg.Sampler = v
+	}
+
+	if v := os.Getenv(EnvSamplerArg); v != "" {
+		ratio, err := strconv.ParseFloat(v, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return cfg, fmt.Errorf("%s: must be a number between 0 and 1; got %q", EnvSamplerArg, v)
+		}
+
+		cfg.SamplerRatio = ratio
+	}
+
+	var err error
+
+	if cfg.BatchTimeout, err = envMilliseconds(EnvBatchTimeout); err != nil {
+		return cfg, err
+	}
+
+	if cfg.ExportTimeout, err = envMilliseconds(EnvExportTimeout); err != nil {
+		return cfg, err
+	}
+
+	if cfg.MaxQueueSize, err = envInt(EnvMaxQueueSize); err != nil {
+		return cfg, err
+	}
+
+	if cfg.MaxExportBatchSize, err = envInt(EnvMaxExportBatchSize); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func envInt(name string) (int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s: must be a positive integer; got %q", name, v)
+	}
+
+	return n, nil
+}
+
+func envMilliseconds(name string) (time.Duration, error) {
+	n, err := envInt(name)
+
+	return time.Duration(n) * time.Millisecond, err
+}
@@ -0,0 +1,127 @@
+package tracing_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/tracing"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(tracing.EnvServiceName, "orders")
+	t.Setenv(tracing.EnvServiceVersion, "1.2.3")
+	t.Setenv(tracing.EnvExporter, tracing.ExporterFile)
+	t.Setenv(tracing.EnvFile, "/tmp/spans.jsonl")
+	t.Setenv(tracing.EnvSampler, tracing.SamplerParentBasedTraceIDRatio)
+	t.Setenv(tracing.EnvSamplerArg, "0.25")
+	t.Setenv(tracing.EnvBatchTimeout, "500")
+	t.Setenv(tracing.EnvMaxExportBatchSize, "64")
+
+	got, err := tracing.FromEnv("app")
+	require.NoError(t, err)
+
+	require.Equal(t, tracing.Config{
+		ServiceName:        "orders",
+		ServiceVersion:     "1.2.3",
+		Exporter:           tracing.ExporterFile,
+		FilePath:           "/tmp/spans.jsonl",
+		Sampler:            tracing.SamplerParentBasedTraceIDRatio,
+		SamplerRatio:       0.25,
+		BatchTimeout:       500 * time.Millisecond,
+		MaxExportBatchSize: 64,
+	}, got)
+}
+
+func TestFromEnv_defaults(t *testing.T) {
+	for _, name := range []string{tracing.EnvServiceName, tracing.EnvExporter, tracing.EnvSampler, tracing.EnvSamplerArg} {
+		t.Setenv(name, "")
+	}
+
+	got, err := tracing.FromEnv("app")
+	require.NoError(t, err)
+
+	require.Equal(t, "app", got.ServiceName)
+	require.Equal(t, tracing.ExporterNone, got.Exporter)
+	require.Equal(t, tracing.SamplerParentBasedAlwaysOn, got.Sampler)
+}
+
+func TestFromEnv_invalid(t *testing.T) {
+	t.Setenv(tracing.EnvSamplerArg, "2")
+
+	_, err := tracing.FromEnv("app")
+	require.ErrorContains(t, err, tracing.EnvSamplerArg)
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+
+	tests := []struct {
+		name     string
+		cfg      tracing.Config
+		sampled  bool
+		wantFile string
+		wantErr  bool
+	}{
+		{
+			name:     "file exporter",
+			cfg:      tracing.Config{ServiceName: "app", Exporter: tracing.ExporterFile, FilePath: path, Sampler: tracing.SamplerAlwaysOn},
+			sampled:  true,
+			wantFile: path,
+		},
+		{
+			name: "never sampled",
+			cfg:  tracing.Config{ServiceName: "app", Sampler: tracing.SamplerParentBasedAlwaysOff},
+		},
+		{
+			name:    "file exporter without path",
+			cfg:     tracing.Config{Exporter: tracing.ExporterFile},
+			wantErr: true,
+		},
+		{
+			name:    "unknown exporter",
+			cfg:     tracing.Config{Exporter: "zipkin"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown sampler",
+			cfg:     tracing.Config{Sampler: "sometimes"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tp, err := tracing.NewTracerProvider(context.Background(), tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			_, span := tp.Tracer("test").Start(context.Background(), "span")
+			span.End()
+
+			require.Equal(t, tt.sampled, span.SpanContext().IsSampled())
+			require.NoError(t, tp.Shutdown(context.Background()))
+
+			if tt.wantFile == "" {
+				return
+			}
+
+			data, err := os.ReadFile(tt.wantFile)
+			require.NoError(t, err)
+			require.Contains(t, string(data), `"service.name":"app"`)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var _ sdktrace.SpanExporter = (*JSONExporter)(nil)
+
+// JSONExporter writes every span as one JSON object per line.
+type JSONExporter struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	closer  io.Closer
+	stopped bool
+}
+
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{enc: json.NewEncoder(w)}
+}
+
+// NewFileExporter appends spans to path, creating it if needed. The file is
+// closed on Shutdown.
+func NewFileExporter(path string) (*JSONExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("openFile: %w", err)
+	}
+
+	e := NewJSONExporter(f)
+	e.closer = f
+
+	return e, nil
+}
+
+func (e *JSONExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stopped {
+		return nil
+	}
+
+	for _, span := range spans {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := e.enc.Encode(newJSONSpan(span)); err != nil {
+			return fmt.Errorf("encode: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (e *JSONExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stopped {
+		return nil
+	}
+
+	e.stopped = true
+
+	if e.closer != nil {
+		return e.closer.Close()
+	}
+
+	return nil
+}
+
+type jsonSpan struct {
+	TraceID      string         `json:"traceId"`
+	SpanID       string         `json:"spanId"`
+	ParentSpanID string         `json:"parentSpanId,omitempty"`
+	Name         string         `json:"name"`
+	Kind         string         `json:"kind"`
+	StartTime    time.Time      `json:"startTime"`
+	EndTime      time.Time      `json:"endTime"`
+	DurationMS   float64        `json:"durationMs"`
+	Status       jsonStatus     `json:"status"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Events       []jsonEvent    `json:"events,omitempty"`
+	Links        []jsonLink     `json:"links,omitempty"`
+	Scope        string         `json:"scope,omitempty"`
+	Resource     map[string]any `json:"resource,omitempty"`
+}
+
+type jsonStatus struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+}
+
+type jsonEvent struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type jsonLink struct {
+	TraceID    string         `json:"traceId"`
+	SpanID     string         `json:"spanId"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+func newJSONSpan(span sdktrace.ReadOnlySpan) jsonSpan {
+	out := jsonSpan{
+		TraceID:    span.SpanContext().TraceID().String(),
+		SpanID:     span.SpanContext().SpanID().String(),
+		Name:       span.Name(),
+		Kind:       span.SpanKind().String(),
+		StartTime:  span.StartTime(),
+		EndTime:    span.EndTime(),
+		DurationMS: float64(span.EndTime().Sub(span.StartTime()).Microseconds()) / 1000,
+		Status: jsonStatus{
+			Code:        span.Status().Code.String(),
+			Description: span.Status().Description,
+		},
+		Attributes: attributes(span.Attributes()),
+		Scope:      span.InstrumentationScope().Name,
+	}
+
+	if parent := span.Parent(); parent.SpanID().IsValid() {
+		out.ParentSpanID = parent.SpanID().String()
+	}
+
+	if res := span.Resource(); res != nil {
+		out.Resource = attributes(res.Attributes())
+	}
+
+	for _, event := range span.Events() {
+		out.Events = append(out.Events, jsonEvent{
+			Name:       event.Name,
+			Time:       event.Time,
+			Attributes: attributes(event.Attributes),
+		})
+	}
+
+	for _, link := range span.Links() {
+		out.Links = append(out.Links, jsonLink{
+			TraceID:    link.SpanContext.TraceID().String(),
+			SpanID:     link.SpanContext.SpanID().String(),
+			Attributes: attributes(link.Attributes),
+		})
+	}
+
+	return out
+}
+
+func attributes(kvs []attribute.KeyValue) map[string]any {
+	if len(kvs) == 0 {
+		return nil
+	}
+
+	out := make(map[string]any, len(kvs))
+
+	for _, kv := range kvs {
+		out[string(kv.Key)] = kv.Value.AsInterface()
+	}
+
+	return out
+}
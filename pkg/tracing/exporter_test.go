@@ -0,0 +1,95 @@
+package tracing_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readSpans(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+
+	var out []map[string]any
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		span := map[string]any{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &span))
+
+		out = append(out, span)
+	}
+
+	return out
+}
+
+func TestJSONExporter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf bytes.Buffer
+		tp  = sdktrace.NewTracerProvider(sdktrace.WithSyncer(tracing.NewJSONExporter(&buf)))
+	)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, child := tp.Tracer("test").Start(ctx, "child")
+	child.SetAttributes(attribute.Int("quantity", 251))
+	child.AddEvent("solved")
+	child.RecordError(errors.New("boom"))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	spans := readSpans(t, buf.Bytes())
+	require.Len(t, spans, 2)
+
+	got := spans[0]
+	require.Equal(t, "child", got["name"])
+	require.Equal(t, parent.SpanContext().TraceID().String(), got["traceId"])
+	require.Equal(t, parent.SpanContext().SpanID().String(), got["parentSpanId"])
+	require.Equal(t, "internal", got["kind"])
+	require.Equal(t, "test", got["scope"])
+	require.Equal(t, map[string]any{"code": "Error", "description": "boom"}, got["status"])
+	require.Equal(t, map[string]any{"quantity": float64(251)}, got["attributes"])
+	require.Len(t, got["events"], 2)
+	require.Contains(t, got["resource"], "service.name")
+
+	require.Equal(t, "parent", spans[1]["name"])
+	require.NotContains(t, spans[1], "parentSpanId")
+}
+
+func TestNewFileExporter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+
+	exporter, err := tracing.NewFileExporter(path)
+	require.NoError(t, err)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+
+	for _, name := range []string{"a", "b"} {
+		_, span := tp.Tracer("test").Start(context.Background(), name)
+		span.End()
+	}
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, readSpans(t, data), 2)
+
+	// spans ended after the shutdown are dropped rather than failing
+	require.NoError(t, exporter.ExportSpans(context.Background(), nil))
+}
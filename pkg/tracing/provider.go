@@ -0,0 +1,139 @@
+// Package tracing configures the OpenTelemetry tracer provider: where spans
+// are exported, how they are sampled and which resource describes the service.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"os"
+)
+
+// Setup builds the tracer provider described by cfg and installs it, together
+// with the W3C TraceContext and Baggage propagators, as the global one. The
+// caller must shut it down to flush the spans still batched.
+func Setup(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	tp, err := NewTracerProvider(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp, nil
+}
+
+func NewTracerProvider(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("newResource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(res),
+	}
+
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter, batchOptions(cfg)...))
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+func newExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return NewJSONExporter(os.Stdout), nil
+	case ExporterFile:
+		if cfg.FilePath == "" {
+			return nil, fmt.Errorf("exporter %q requires a file path", ExporterFile)
+		}
+
+		return NewFileExporter(cfg.FilePath)
+	}
+
+	return nil, fmt.Errorf("unknown exporter %q", cfg.Exporter)
+}
+
+func newSampler(cfg Config) (sdktrace.Sampler, error) {
+	switch cfg.Sampler {
+	case SamplerAlwaysOn:
+		return sdktrace.AlwaysSample(), nil
+	case SamplerAlwaysOff:
+		return sdktrace.NeverSample(), nil
+	case SamplerTraceIDRatio:
+		return sdktrace.TraceIDRatioBased(cfg.SamplerRatio), nil
+	case "", SamplerParentBasedAlwaysOn:
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case SamplerParentBasedAlwaysOff:
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case SamplerParentBasedTraceIDRatio:
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplerRatio)), nil
+	}
+
+	return nil, fmt.Errorf("unknown sampler %q", cfg.Sampler)
+}
+
+// newResource lets OTEL_RESOURCE_ATTRIBUTES override the attributes from cfg.
+func newResource(ctx context.Context, cfg Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceName(cfg.ServiceName)}
+
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.ServiceVersion))
+	}
+
+	if cfg.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(cfg.Environment))
+	}
+
+	return resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(attrs...),
+		resource.WithFromEnv(),
+	)
+}
+
+func batchOptions(cfg Config) []sdktrace.BatchSpanProcessorOption {
+	var out []sdktrace.BatchSpanProcessorOption
+
+	if cfg.BatchTimeout > 0 {
+		out = append(out, sdktrace.WithBatchTimeout(cfg.BatchTimeout))
+	}
+
+	if cfg.ExportTimeout > 0 {
+		out = append(out, sdktrace.WithExportTimeout(cfg.ExportTimeout))
+	}
+
+	if cfg.MaxQueueSize > 0 {
+		out = append(out, sdktrace.WithMaxQueueSize(cfg.MaxQueueSize))
+	}
+
+	if cfg.MaxExportBatchSize > 0 {
+		out = append(out, sdktrace.WithMaxExportBatchSize(cfg.MaxExportBatchSize))
+	}
+
+	return out
+}
@@ -0,0 +1,285 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const tagName = "validate"
+
+type FieldError struct {
+	Path    string `json:"path" xml:"path"`
+	Message string `json:"message" xml:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return e.Path + ": " + e.Message
+}
+
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+type Rule struct {
+	Name  string
+	Param string
+}
+
+// Rules parses a tag like `validate:"required,min=1,oneof=a b"`.
+func Rules(tag string) []Rule {
+	var out []Rule
+
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, param, _ := strings.Cut(part, "=")
+		out = append(out, Rule{Name: name, Param: param})
+	}
+
+	return out
+}
+
+// Present is the set of paths the client sent, keyed the way errors are
+// reported: "items[1].size".
+type Present map[string]bool
+
+// JSONPresent collects the paths of every member of the JSON value in data.
+func JSONPresent(data []byte) (Present, error) {
+	var v any
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	out := Present{}
+	collectPaths(v, "", out)
+
+	return out, nil
+}
+
+func collectPaths(v any, path string, out Present) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+
+			out[childPath] = true
+			collectPaths(child, childPath, out)
+		}
+	case []any:
+		for i, child := range v {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+
+			out[childPath] = true
+			collectPaths(child, childPath, out)
+		}
+	}
+}
+
+type Option func(*options)
+
+type options struct {
+	present Present
+}
+
+// WithPresent makes "required" mean the path was sent, like OpenAPI required,
+// instead of the field being non-zero. A present zero value is then reported
+// by the rules that follow, e.g. min. A nil set keeps the zero value check.
+func WithPresent(present Present) Option {
+	return func(o *options) {
+		o.present = present
+	}
+}
+
+// Validate walks v recursively and reports every failed rule by its JSON path.
+func Validate(v any, opts ...Option) error {
+	var (
+		errs Errors
+		o    options
+	)
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	validateValue(reflect.ValueOf(v), "", o, &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateValue(v reflect.Value, path string, o options, errs *Errors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		validateStruct(v, path, o, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), o, errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), o, errs)
+		}
+	}
+}
+
+func validateStruct(v reflect.Value, path string, o options, errs *Errors) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := FieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		fv := v.Field(i)
+
+		present := !fv.IsZero()
+		if o.present != nil {
+			present = o.present[fieldPath]
+		}
+
+		for _, rule := range Rules(field.Tag.Get(tagName)) {
+			if msg, ok := check(rule, fv, present); !ok {
+				*errs = append(*errs, FieldError{Path: fieldPath, Message: msg})
+
+				// one message per field is enough; the following rules usually
+				// repeat the same problem
+				break
+			}
+		}
+
+		validateValue(fv, fieldPath, o, errs)
+	}
+}
+
+func FieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+func check(rule Rule, v reflect.Value, present bool) (string, bool) {
+	switch rule.Name {
+	case "required":
+		if !present {
+			return "is required", false
+		}
+	case "min":
+		return checkBound(rule, v, func(got, want float64) bool { return got >= want })
+	case "max":
+		return checkBound(rule, v, func(got, want float64) bool { return got <= want })
+	case "oneof":
+		if !present {
+			return "", true
+		}
+
+		allowed := strings.Fields(rule.Param)
+		got := fmt.Sprint(indirect(v).Interface())
+
+		for _, a := range allowed {
+			if got == a {
+				return "", true
+			}
+		}
+
+		return fmt.Sprintf("must be one of [%s]; got %q", strings.Join(allowed, ", "), got), false
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q", rule.Name))
+	}
+
+	return "", true
+}
+
+func checkBound(rule Rule, v reflect.Value, ok func(got, want float64) bool) (string, bool) {
+	want, err := strconv.ParseFloat(rule.Param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid %s parameter %q", rule.Name, rule.Param))
+	}
+
+	v = indirect(v)
+
+	var (
+		got    float64
+		suffix string
+	)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		got = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		got = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		got = v.Float()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		got = float64(v.Len())
+		suffix = " in length"
+	default:
+		return "", true
+	}
+
+	if ok(got, want) {
+		return "", true
+	}
+
+	op := "greater than or equal to"
+	if rule.Name == "max" {
+		op = "less than or equal to"
+	}
+
+	return fmt.Sprintf("must be %s %s%s; got %v", op, rule.Param, suffix, got), false
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v
+}
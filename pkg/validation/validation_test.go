@@ -0,0 +1,124 @@
+package validation_test
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/vcraescu/gsh-assessment/pkg/validation"
+	"testing"
+)
+
+type item struct {
+	Size int `json:"size" validate:"min=1,max=10"`
+}
+
+type payload struct {
+	Name   string  `json:"name" validate:"required,max=5"`
+	Kind   string  `json:"kind,omitempty" validate:"oneof=small large"`
+	Count  *int    `json:"count" validate:"min=0"`
+	Items  []item  `json:"items" validate:"max=2"`
+	Ignore string  `json:"-" validate:"required"`
+	Nested *item   `json:"nested"`
+	Ratio  float64 `validate:"max=0.5"`
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	negative := -1
+
+	tests := []struct {
+		name string
+		in   any
+		opts []validation.Option
+		want validation.Errors
+	}{
+		{
+			name: "valid",
+			in: payload{
+				Name:  "abc",
+				Kind:  "small",
+				Items: []item{{Size: 1}},
+			},
+		},
+		{
+			name: "required",
+			in:   &payload{},
+			want: validation.Errors{
+				{Path: "name", Message: "is required"},
+			},
+		},
+		{
+			name: "required by presence",
+			in:   payload{Kind: ""},
+			opts: []validation.Option{validation.WithPresent(validation.Present{"kind": true})},
+			want: validation.Errors{
+				{Path: "name", Message: "is required"},
+				{Path: "kind", Message: `must be one of [small, large]; got ""`},
+			},
+		},
+		{
+			name: "present zero value",
+			in:   payload{Items: []item{{}}},
+			opts: []validation.Option{validation.WithPresent(validation.Present{
+				"name": true, "items": true, "items[0]": true, "items[0].size": true,
+			})},
+			want: validation.Errors{
+				{Path: "items[0].size", Message: "must be greater than or equal to 1; got 0"},
+			},
+		},
+		{
+			name: "nested paths",
+			in: payload{
+				Name:   "abcdef",
+				Kind:   "medium",
+				Count:  &negative,
+				Items:  []item{{Size: 1}, {Size: 11}, {Size: 0}},
+				Nested: &item{},
+				Ratio:  0.75,
+			},
+			want: validation.Errors{
+				{Path: "name", Message: "must be less than or equal to 5 in length; got 6"},
+				{Path: "kind", Message: `must be one of [small, large]; got "medium"`},
+				{Path: "count", Message: "must be greater than or equal to 0; got -1"},
+				{Path: "items", Message: "must be less than or equal to 2 in length; got 3"},
+				{Path: "items[1].size", Message: "must be less than or equal to 10; got 11"},
+				{Path: "items[2].size", Message: "must be greater than or equal to 1; got 0"},
+				{Path: "nested.size", Message: "must be greater than or equal to 1; got 0"},
+				{Path: "Ratio", Message: "must be less than or equal to 0.5; got 0.75"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validation.Validate(tt.in, tt.opts...)
+			if tt.want == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestJSONPresent(t *testing.T) {
+	t.Parallel()
+
+	got, err := validation.JSONPresent([]byte(`{"name":"","items":[{"size":0}],"nested":null}`))
+	require.NoError(t, err)
+	require.Equal(t, validation.Present{
+		"name":          true,
+		"items":         true,
+		"items[0]":      true,
+		"items[0].size": true,
+		"nested":        true,
+	}, got)
+
+	_, err = validation.JSONPresent([]byte(`{`))
+	require.Error(t, err)
+}
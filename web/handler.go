@@ -11,6 +11,9 @@ var (
 	//go:embed static/*
 	staticFS     embed.FS
 	contentFS, _ = fs.Sub(staticFS, "static")
+
+	//go:embed docs/index.html
+	docsPage []byte
 )
 
 func StaticHandler(w http.ResponseWriter, r *http.Request) error {
@@ -18,3 +21,12 @@ func StaticHandler(w http.ResponseWriter, r *http.Request) error {
 
 	return nil
 }
+
+func DocsHandler(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	_, err := w.Write(docsPage)
+
+	return err
+}